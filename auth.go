@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type authAPI interface {
@@ -16,17 +17,31 @@ type authAPI interface {
 	SignInWithOAuth(ctx context.Context, body OAuthSignInRequest) (string, error)
 	SignInWithOTP(ctx context.Context, body SignInRequest) error
 	SignInWithPassword(ctx context.Context, body SignInRequest) (*AuthDetailResp, error)
-	SignOut(ctx context.Context, token string) error
+	SignOut(ctx context.Context, token string, scope ...SignOutScope) error
 	SignUp(ctx context.Context, credentials SignUpRequest) (*AuthDetailResp, error)
 	User(ctx context.Context, token string) (*User, error)
 	UpdateUser(ctx context.Context, token string, body UpdateUserRequest) (*User, error)
 	Verify(ctx context.Context, body VerifyRequest) (*AuthDetailResp, error)
+	SignInWithSSO(ctx context.Context, body SignInWithSSORequest) (string, error)
+	ExchangeCodeForSession(ctx context.Context, body ExchangeCodeRequest) (*AuthDetailResp, error)
+	Resend(ctx context.Context, body ResendRequest) (*ResendResp, error)
+	Reauthenticate(ctx context.Context, token string) error
+	LinkIdentity(ctx context.Context, token string, body LinkIdentityRequest) (string, error)
+	UnlinkIdentity(ctx context.Context, token, identityID string) error
+	GetUserIdentities(ctx context.Context, token string) ([]Identity, error)
+	Settings(ctx context.Context) (*AuthSettings, error)
+	OnAuthStateChange(callback AuthStateChangeFunc) *AuthSubscription
+	Admin() authAdminAPI
+	MFA() mfaAPI
 }
 
 type Auth struct {
-	apiKey     string
-	authHost   string
-	httpClient Sender
+	apiKey         string
+	serviceRoleKey string
+	authHost       string
+	httpClient     Sender
+	events         *authEvents
+	userCache      *userCache
 }
 
 type AuthOption func(c *Auth)
@@ -37,11 +52,30 @@ func WithAuthClient(httpClient *http.Client, header map[string]string) AuthOptio
 	}
 }
 
+// WithServiceRoleKey sets the service_role key used by the admin API. When it is not set, the api key is used.
+func WithServiceRoleKey(serviceRoleKey string) AuthOption {
+	return func(c *Auth) {
+		c.serviceRoleKey = serviceRoleKey
+	}
+}
+
+// WithUserCache caches the users resolved by User, keyed by the sha256 hash of the token. The cache holds
+// at most maxEntries users, and an entry expires with its token or after maxTTL when maxTTL is positive.
+// Concurrent lookups of the same token share one request. UpdateUser and SignOut through the same client
+// invalidate the cached user, but changes made elsewhere are only seen once the entry expires.
+func WithUserCache(maxEntries int, maxTTL time.Duration) AuthOption {
+	return func(c *Auth) {
+		c.userCache = newUserCache(maxEntries, maxTTL)
+	}
+}
+
 func NewAuth(apiKey, authHost string, options ...AuthOption) *Auth {
 	impl := &Auth{
-		apiKey:     apiKey,
-		authHost:   authHost,
-		httpClient: defaultSender(httpTimeout, make(map[string]string)),
+		apiKey:         apiKey,
+		serviceRoleKey: apiKey,
+		authHost:       authHost,
+		httpClient:     defaultSender(httpTimeout, make(map[string]string)),
+		events:         newAuthEvents(),
 	}
 	for _, opt := range options {
 		opt(impl)
@@ -49,6 +83,13 @@ func NewAuth(apiKey, authHost string, options ...AuthOption) *Auth {
 	return impl
 }
 
+// OnAuthStateChange registers a callback which receives an event every time a session is signed in,
+// signed out, refreshed or its user is updated through this client. Events are delivered on a separate
+// goroutine in the order they happened, so the callback never blocks the auth calls.
+func (i Auth) OnAuthStateChange(callback AuthStateChangeFunc) *AuthSubscription {
+	return i.events.subscribe(callback)
+}
+
 // ResetPasswordForEmail sends a password reset request to an email address. This method supports the PKCE flow.
 func (i Auth) ResetPasswordForEmail(ctx context.Context, body ResetPasswordForEmailRequest) error {
 	reqURL := fmt.Sprintf("%s/recover", i.authHost)
@@ -61,7 +102,7 @@ func (i Auth) ResetPasswordForEmail(ctx context.Context, body ResetPasswordForEm
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in reset password for email due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	return nil
 }
@@ -78,7 +119,7 @@ func (i Auth) SignInWithOTP(ctx context.Context, body SignInRequest) error {
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in get sign in with otp due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	return nil
 }
@@ -95,7 +136,7 @@ func (i Auth) SignInWithPassword(ctx context.Context, body SignInRequest) (*Auth
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in sign in with password due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var authDetail *AuthDetailResp
 	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
@@ -103,6 +144,7 @@ func (i Auth) SignInWithPassword(ctx context.Context, body SignInRequest) (*Auth
 		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
 		return nil, err
 	}
+	i.events.emit(AuthChangeEventSignedIn, authDetail)
 	return authDetail, nil
 }
 
@@ -118,7 +160,7 @@ func (i Auth) SignUp(ctx context.Context, body SignUpRequest) (*AuthDetailResp,
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in sign up due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var authDetail *AuthDetailResp
 	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
@@ -126,13 +168,27 @@ func (i Auth) SignUp(ctx context.Context, body SignUpRequest) (*AuthDetailResp,
 		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
 		return nil, err
 	}
+	// No session is returned when the email or phone needs to be confirmed first.
+	if authDetail != nil && authDetail.AccessToken != "" {
+		i.events.emit(AuthChangeEventSignedIn, authDetail)
+	}
 	return authDetail, nil
 }
 
 // User gets the current user details if there is an existing session. This method
 // performs a network request to the Supabase Auth server, so the returned
 // value is authentic and can be used to base authorization rules on.
+// With WithUserCache, the user is served from the cache until the token expires.
 func (i Auth) User(ctx context.Context, token string) (*User, error) {
+	if i.userCache == nil {
+		return i.fetchUser(ctx, token)
+	}
+	return i.userCache.user(ctx, token, func(ctx context.Context) (*User, error) {
+		return i.fetchUser(ctx, token)
+	})
+}
+
+func (i Auth) fetchUser(ctx context.Context, token string) (*User, error) {
 	reqURL := fmt.Sprintf("%s/user", i.authHost)
 	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, func(req *http.Request) {
 		req.Header.Set(authorizationHeader, i.apiKey)
@@ -144,7 +200,7 @@ func (i Auth) User(ctx context.Context, token string) (*User, error) {
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in get user due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var user *User
 	err = json.Unmarshal(httpResp.Body.Bytes(), &user)
@@ -168,7 +224,7 @@ func (i Auth) UpdateUser(ctx context.Context, token string, body UpdateUserReque
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in update user due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var user *User
 	err = json.Unmarshal(httpResp.Body.Bytes(), &user)
@@ -176,27 +232,61 @@ func (i Auth) UpdateUser(ctx context.Context, token string, body UpdateUserReque
 		logger.Error("failed in unmarshal update user json with err: %s", err)
 		return nil, err
 	}
+	i.userCache.invalidateUser(user.ID)
+	i.events.emit(AuthChangeEventUserUpdated, &AuthDetailResp{AccessToken: token, User: *user})
 	return user, nil
 }
 
-// SignOut sign user out
-func (i Auth) SignOut(ctx context.Context, token string) error {
-	reqURL := fmt.Sprintf("%s/logout?scope=global", i.authHost)
-	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, nil, func(req *http.Request) {
-		req.Header.Set(authorizationHeader, i.apiKey)
+// SignOut sign user out. Scope defaults to SignOutScopeGlobal which ends every session of the user,
+// SignOutScopeLocal ends only the session of the token and SignOutScopeOthers ends every other session.
+// A 401 or 404 from the api means the session is already gone, so it is treated as signed out.
+func (i Auth) SignOut(ctx context.Context, token string, scope ...SignOutScope) error {
+	if err := signOut(ctx, i.httpClient, i.authHost, i.apiKey, token, scope...); err != nil {
+		return err
+	}
+	invalidateSignOut(i.userCache, token, scope)
+	// The session of the token stays valid when only the other sessions are signed out.
+	if len(scope) == 0 || scope[0] != SignOutScopeOthers {
+		i.events.emit(AuthChangeEventSignedOut, &AuthDetailResp{AccessToken: token})
+	}
+	return nil
+}
+
+func signOut(ctx context.Context, httpClient Sender, authHost, apiKey, token string, scope ...SignOutScope) error {
+	signOutScope := SignOutScopeGlobal
+	if len(scope) > 0 {
+		signOutScope = scope[0]
+	}
+	reqURL := fmt.Sprintf("%s/logout?scope=%s", authHost, signOutScope)
+	httpResp, err := httpClient.Call(ctx, reqURL, http.MethodPost, nil, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, apiKey)
 		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
 	})
 	if err != nil {
 		logger.Error("failed in sign out httpclient call with err: %s", err)
 		return err
 	}
+	if httpResp.StatusCode == http.StatusUnauthorized || httpResp.StatusCode == http.StatusNotFound {
+		logger.Debug("getting %d in sign out, session is already signed out", httpResp.StatusCode)
+		return nil
+	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in sign out due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	return nil
 }
 
+// invalidateSignOut removes the signed out token from the cache, and every token of the user unless
+// only the session of the token is signed out.
+func invalidateSignOut(cache *userCache, token string, scope []SignOutScope) {
+	if len(scope) > 0 && scope[0] == SignOutScopeLocal {
+		cache.invalidateToken(token)
+		return
+	}
+	cache.invalidateSession(token)
+}
+
 func (i Auth) Verify(ctx context.Context, body VerifyRequest) (*AuthDetailResp, error) {
 	reqURL := fmt.Sprintf("%s/verify", i.authHost)
 	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
@@ -208,7 +298,7 @@ func (i Auth) Verify(ctx context.Context, body VerifyRequest) (*AuthDetailResp,
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in verify due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var authDetail *AuthDetailResp
 	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
@@ -216,6 +306,11 @@ func (i Auth) Verify(ctx context.Context, body VerifyRequest) (*AuthDetailResp,
 		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
 		return nil, err
 	}
+	if body.Type == VerifyTypeRecovery.String() {
+		i.events.emit(AuthChangeEventPasswordRecovery, authDetail)
+	} else {
+		i.events.emit(AuthChangeEventSignedIn, authDetail)
+	}
 	return authDetail, nil
 }
 
@@ -234,7 +329,7 @@ func (i Auth) RefreshToken(ctx context.Context, refreshToken string) (*AuthDetai
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in refresh token due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var authDetail *AuthDetailResp
 	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
@@ -242,6 +337,7 @@ func (i Auth) RefreshToken(ctx context.Context, refreshToken string) (*AuthDetai
 		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
 		return nil, err
 	}
+	i.events.emit(AuthChangeEventTokenRefreshed, authDetail)
 	return authDetail, nil
 }
 
@@ -282,7 +378,7 @@ func (i Auth) SignInWithIDToken(ctx context.Context, body SignInWithIDTokenReque
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in sign in with id token due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		return nil, External(httpResp.Body.Bytes(), httpResp.StatusCode)
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
 	var authDetail *AuthDetailResp
 	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
@@ -290,5 +386,186 @@ func (i Auth) SignInWithIDToken(ctx context.Context, body SignInWithIDTokenReque
 		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
 		return nil, err
 	}
+	i.events.emit(AuthChangeEventSignedIn, authDetail)
 	return authDetail, nil
 }
+
+// LinkIdentity returns the url to authorize linking an oauth identity to the current user.
+// Provider takes one of the Provider values. Anonymous users can be upgraded to a permanent user this way.
+func (i Auth) LinkIdentity(ctx context.Context, token string, body LinkIdentityRequest) (string, error) {
+	// Let the api return the url as json instead of redirecting, so the bearer token can be sent.
+	body.SkipHTTPRedirect = true
+	reqURL := fmt.Sprintf("%s/user/identities/authorize", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in link identity httpclient call with err: %s", err)
+		return "", err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in link identity due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return "", authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var link LinkIdentityResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &link)
+	if err != nil {
+		logger.Error("failed in unmarshal link identity json with err: %s", err)
+		return "", err
+	}
+	i.userCache.invalidateSession(token)
+	return link.URL, nil
+}
+
+// UnlinkIdentity unlinks an identity from the current user. A user must keep at least one identity.
+func (i Auth) UnlinkIdentity(ctx context.Context, token, identityID string) error {
+	reqURL := fmt.Sprintf("%s/user/identities/%s", i.authHost, identityID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodDelete, nil, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in unlink identity httpclient call with err: %s", err)
+		return err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in unlink identity due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	i.userCache.invalidateSession(token)
+	return nil
+}
+
+// GetUserIdentities gets all the identities linked to the current user.
+func (i Auth) GetUserIdentities(ctx context.Context, token string) ([]Identity, error) {
+	user, err := i.User(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return user.Identities, nil
+}
+
+// Resend resends an existing signup confirmation email, email change email, SMS OTP or phone change OTP.
+// MessageID is only returned for SMS.
+func (i Auth) Resend(ctx context.Context, body ResendRequest) (*ResendResp, error) {
+	switch body.Type {
+	case VerifyTypeSignUp, VerifyTypeEmailChange, VerifyTypeSms, VerifyTypePhoneChange:
+	default:
+		return nil, ErrUnsupportedResendType
+	}
+	reqURL := fmt.Sprintf("%s/resend", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+	})
+	if err != nil {
+		logger.Error("failed in resend httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in resend due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var resend *ResendResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &resend)
+	if err != nil {
+		logger.Error("failed in unmarshal resend json with err: %s", err)
+		return nil, err
+	}
+	return resend, nil
+}
+
+// Reauthenticate sends a nonce to the user's email or phone. The nonce is passed as Nonce
+// in UpdateUserRequest when the project requires reauthentication to change the password.
+func (i Auth) Reauthenticate(ctx context.Context, token string) error {
+	reqURL := fmt.Sprintf("%s/reauthenticate", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in reauthenticate httpclient call with err: %s", err)
+		return err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in reauthenticate due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	return nil
+}
+
+// SignInWithSSO returns the url of the identity provider to sign in with enterprise SSO (SAML).
+// Set CodeChallenge from NewPKCE to use the PKCE flow, then exchange the returned code with ExchangeCodeForSession.
+func (i Auth) SignInWithSSO(ctx context.Context, body SignInWithSSORequest) (string, error) {
+	if (body.Domain == "") == (body.ProviderID == "") {
+		return "", ErrSSODomainOrProviderID
+	}
+	// Let the api return the url as json instead of redirecting.
+	body.SkipHTTPRedirect = true
+	reqURL := fmt.Sprintf("%s/sso", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+	})
+	if err != nil {
+		logger.Error("failed in sign in with sso httpclient call with err: %s", err)
+		return "", err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in sign in with sso due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return "", authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var sso SignInWithSSOResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &sso)
+	if err != nil {
+		logger.Error("failed in unmarshal sign in with sso json with err: %s", err)
+		return "", err
+	}
+	return sso.URL, nil
+}
+
+// ExchangeCodeForSession exchanges the auth code of the PKCE flow for a session.
+func (i Auth) ExchangeCodeForSession(ctx context.Context, body ExchangeCodeRequest) (*AuthDetailResp, error) {
+	reqURL := fmt.Sprintf("%s/token?grant_type=pkce", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+	})
+	if err != nil {
+		logger.Error("failed in exchange code for session httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in exchange code for session due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var authDetail *AuthDetailResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
+	if err != nil {
+		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
+		return nil, err
+	}
+	i.events.emit(AuthChangeEventSignedIn, authDetail)
+	return authDetail, nil
+}
+
+// Settings gets the public settings of the auth server, such as the enabled oauth providers.
+func (i Auth) Settings(ctx context.Context) (*AuthSettings, error) {
+	reqURL := fmt.Sprintf("%s/settings", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.apiKey)
+	})
+	if err != nil {
+		logger.Error("failed in settings httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in settings due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var settings *AuthSettings
+	err = json.Unmarshal(httpResp.Body.Bytes(), &settings)
+	if err != nil {
+		logger.Error("failed in unmarshal settings json with err: %s", err)
+		return nil, err
+	}
+	return settings, nil
+}
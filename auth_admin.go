@@ -0,0 +1,376 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type authAdminAPI interface {
+	ListUsers(ctx context.Context, params ListUsersRequest) (*ListUsersResp, error)
+	SignOut(ctx context.Context, jwt string, scope ...SignOutScope) error
+	GetUserByID(ctx context.Context, userID string) (*User, error)
+	CreateUser(ctx context.Context, body AdminUserAttributes) (*User, error)
+	UpdateUserByID(ctx context.Context, userID string, body AdminUserAttributes) (*User, error)
+	DeleteUser(ctx context.Context, userID string, shouldSoftDelete bool) error
+	InviteUserByEmail(ctx context.Context, body InviteUserByEmailRequest) (*User, error)
+	GenerateLink(ctx context.Context, body GenerateLinkRequest) (*GenerateLinkResp, error)
+	ListFactors(ctx context.Context, userID string) ([]Factor, error)
+	DeleteFactor(ctx context.Context, userID, factorID string) error
+	ListSSOProviders(ctx context.Context) ([]SSOProvider, error)
+	GetSSOProvider(ctx context.Context, providerID string) (*SSOProvider, error)
+	CreateSSOProvider(ctx context.Context, body SSOProviderRequest) (*SSOProvider, error)
+	UpdateSSOProvider(ctx context.Context, providerID string, body SSOProviderRequest) (*SSOProvider, error)
+	DeleteSSOProvider(ctx context.Context, providerID string) error
+}
+
+// AuthAdmin refer from https://github.com/supabase/gotrue-js/blob/master/src/GoTrueAdminApi.ts
+// Every request is authorised with the service_role key, so it must only be used on a trusted server.
+type AuthAdmin struct {
+	apiKey         string
+	serviceRoleKey string
+	authHost       string
+	httpClient     Sender
+	userCache      *userCache
+}
+
+// Admin returns the admin API which is authorised with the service_role key.
+func (i Auth) Admin() authAdminAPI {
+	return &AuthAdmin{
+		apiKey:         i.apiKey,
+		serviceRoleKey: i.serviceRoleKey,
+		authHost:       i.authHost,
+		httpClient:     i.httpClient,
+		userCache:      i.userCache,
+	}
+}
+
+func (i AuthAdmin) setHeader(req *http.Request) {
+	req.Header.Set(authorizationHeader, i.apiKey)
+	req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, i.serviceRoleKey))
+}
+
+// ListUsers gets a page of users. Page starts from 1 and the pagination details are parsed from the response headers.
+func (i AuthAdmin) ListUsers(ctx context.Context, params ListUsersRequest) (*ListUsersResp, error) {
+	reqURL := fmt.Sprintf("%s/admin/users", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, params, i.setHeader)
+	if err != nil {
+		logger.Error("failed in list users httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in list users due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var users *ListUsersResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &users)
+	if err != nil {
+		logger.Error("failed in unmarshal list users json with err: %s", err)
+		return nil, err
+	}
+	users.Total, _ = strconv.Atoi(httpResp.Header.Get("X-Total-Count"))
+	users.NextPage, users.LastPage = parsePageLinks(httpResp.Header.Get("Link"))
+	return users, nil
+}
+
+// parsePageLinks reads the next and last page number from a link header such as
+// `</admin/users?page=2&per_page=50>; rel="next", </admin/users?page=4&per_page=50>; rel="last"`.
+func parsePageLinks(link string) (nextPage, lastPage int) {
+	for _, part := range strings.Split(link, ",") {
+		target, rel, found := strings.Cut(part, ";")
+		if !found {
+			continue
+		}
+		linkURL, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			continue
+		}
+		page, err := strconv.Atoi(linkURL.Query().Get("page"))
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSpace(rel) {
+		case `rel="next"`:
+			nextPage = page
+		case `rel="last"`:
+			lastPage = page
+		}
+	}
+	return nextPage, lastPage
+}
+
+// GetUserByID gets the user by their id.
+func (i AuthAdmin) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	reqURL := fmt.Sprintf("%s/admin/users/%s", i.authHost, userID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, i.setHeader)
+	if err != nil {
+		logger.Error("failed in get user by id httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in get user by id due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var user *User
+	err = json.Unmarshal(httpResp.Body.Bytes(), &user)
+	if err != nil {
+		logger.Error("failed in unmarshal user json with err: %s", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateUser creates a new user. Set EmailConfirm or PhoneConfirm to skip the confirmation message.
+func (i AuthAdmin) CreateUser(ctx context.Context, body AdminUserAttributes) (*User, error) {
+	reqURL := fmt.Sprintf("%s/admin/users", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in create user httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in create user due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var user *User
+	err = json.Unmarshal(httpResp.Body.Bytes(), &user)
+	if err != nil {
+		logger.Error("failed in unmarshal create user json with err: %s", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateUserByID updates the user data of the given user id.
+func (i AuthAdmin) UpdateUserByID(ctx context.Context, userID string, body AdminUserAttributes) (*User, error) {
+	reqURL := fmt.Sprintf("%s/admin/users/%s", i.authHost, userID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPut, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in update user by id httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in update user by id due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var user *User
+	err = json.Unmarshal(httpResp.Body.Bytes(), &user)
+	if err != nil {
+		logger.Error("failed in unmarshal update user by id json with err: %s", err)
+		return nil, err
+	}
+	i.userCache.invalidateUser(userID)
+	return user, nil
+}
+
+// DeleteUser deletes the user. A soft deleted user is kept in the database with its identifying data obfuscated.
+func (i AuthAdmin) DeleteUser(ctx context.Context, userID string, shouldSoftDelete bool) error {
+	body := DeleteUserRequest{
+		ShouldSoftDelete: shouldSoftDelete,
+	}
+	reqURL := fmt.Sprintf("%s/admin/users/%s", i.authHost, userID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodDelete, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in delete user httpclient call with err: %s", err)
+		return err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in delete user due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	i.userCache.invalidateUser(userID)
+	return nil
+}
+
+// InviteUserByEmail sends an invite link to an email address.
+func (i AuthAdmin) InviteUserByEmail(ctx context.Context, body InviteUserByEmailRequest) (*User, error) {
+	reqURL := fmt.Sprintf("%s/invite", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in invite user by email httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in invite user by email due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var user *User
+	err = json.Unmarshal(httpResp.Body.Bytes(), &user)
+	if err != nil {
+		logger.Error("failed in unmarshal invite user json with err: %s", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// GenerateLink generates email links and OTPs to be sent via a custom email provider.
+func (i AuthAdmin) GenerateLink(ctx context.Context, body GenerateLinkRequest) (*GenerateLinkResp, error) {
+	reqURL := fmt.Sprintf("%s/admin/generate_link", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in generate link httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in generate link due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var link *GenerateLinkResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &link)
+	if err != nil {
+		logger.Error("failed in unmarshal generate link json with err: %s", err)
+		return nil, err
+	}
+	return link, nil
+}
+
+// ListFactors lists all factors of the user, including unverified ones.
+func (i AuthAdmin) ListFactors(ctx context.Context, userID string) ([]Factor, error) {
+	reqURL := fmt.Sprintf("%s/admin/users/%s/factors", i.authHost, userID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, i.setHeader)
+	if err != nil {
+		logger.Error("failed in list factors httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in list factors due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var factors []Factor
+	err = json.Unmarshal(httpResp.Body.Bytes(), &factors)
+	if err != nil {
+		logger.Error("failed in unmarshal factors json with err: %s", err)
+		return nil, err
+	}
+	return factors, nil
+}
+
+// DeleteFactor deletes a factor of the user, which will log the user out of all their sessions.
+func (i AuthAdmin) DeleteFactor(ctx context.Context, userID, factorID string) error {
+	reqURL := fmt.Sprintf("%s/admin/users/%s/factors/%s", i.authHost, userID, factorID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodDelete, nil, i.setHeader)
+	if err != nil {
+		logger.Error("failed in delete factor httpclient call with err: %s", err)
+		return err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in delete factor due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	return nil
+}
+
+// ListSSOProviders lists all the SAML identity providers of the project.
+func (i AuthAdmin) ListSSOProviders(ctx context.Context) ([]SSOProvider, error) {
+	reqURL := fmt.Sprintf("%s/admin/sso/providers", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, i.setHeader)
+	if err != nil {
+		logger.Error("failed in list sso providers httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in list sso providers due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var providers ListSSOProvidersResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &providers)
+	if err != nil {
+		logger.Error("failed in unmarshal sso providers json with err: %s", err)
+		return nil, err
+	}
+	return providers.Items, nil
+}
+
+// GetSSOProvider gets a SAML identity provider by its id.
+func (i AuthAdmin) GetSSOProvider(ctx context.Context, providerID string) (*SSOProvider, error) {
+	reqURL := fmt.Sprintf("%s/admin/sso/providers/%s", i.authHost, providerID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodGet, nil, i.setHeader)
+	if err != nil {
+		logger.Error("failed in get sso provider httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in get sso provider due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var provider *SSOProvider
+	err = json.Unmarshal(httpResp.Body.Bytes(), &provider)
+	if err != nil {
+		logger.Error("failed in unmarshal sso provider json with err: %s", err)
+		return nil, err
+	}
+	return provider, nil
+}
+
+// CreateSSOProvider registers a SAML identity provider from either its metadata url or metadata xml.
+func (i AuthAdmin) CreateSSOProvider(ctx context.Context, body SSOProviderRequest) (*SSOProvider, error) {
+	if body.Type == "" {
+		body.Type = "saml"
+	}
+	reqURL := fmt.Sprintf("%s/admin/sso/providers", i.authHost)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPost, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in create sso provider httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in create sso provider due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var provider *SSOProvider
+	err = json.Unmarshal(httpResp.Body.Bytes(), &provider)
+	if err != nil {
+		logger.Error("failed in unmarshal sso provider json with err: %s", err)
+		return nil, err
+	}
+	return provider, nil
+}
+
+// UpdateSSOProvider updates a SAML identity provider. Domains replaces all the domains of the provider.
+func (i AuthAdmin) UpdateSSOProvider(ctx context.Context, providerID string, body SSOProviderRequest) (*SSOProvider, error) {
+	reqURL := fmt.Sprintf("%s/admin/sso/providers/%s", i.authHost, providerID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodPut, body, i.setHeader)
+	if err != nil {
+		logger.Error("failed in update sso provider httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in update sso provider due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var provider *SSOProvider
+	err = json.Unmarshal(httpResp.Body.Bytes(), &provider)
+	if err != nil {
+		logger.Error("failed in unmarshal sso provider json with err: %s", err)
+		return nil, err
+	}
+	return provider, nil
+}
+
+// DeleteSSOProvider removes a SAML identity provider.
+func (i AuthAdmin) DeleteSSOProvider(ctx context.Context, providerID string) error {
+	reqURL := fmt.Sprintf("%s/admin/sso/providers/%s", i.authHost, providerID)
+	httpResp, err := i.httpClient.Call(ctx, reqURL, http.MethodDelete, nil, i.setHeader)
+	if err != nil {
+		logger.Error("failed in delete sso provider httpclient call with err: %s", err)
+		return err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in delete sso provider due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	return nil
+}
+
+// SignOut ends the sessions of the user who owns the jwt. It takes the same scope as Auth.SignOut.
+func (i AuthAdmin) SignOut(ctx context.Context, jwt string, scope ...SignOutScope) error {
+	if err := signOut(ctx, i.httpClient, i.authHost, i.serviceRoleKey, jwt, scope...); err != nil {
+		return err
+	}
+	invalidateSignOut(i.userCache, jwt, scope)
+	return nil
+}
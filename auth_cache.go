@@ -0,0 +1,250 @@
+package supabase
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// userCache is a size bounded LRU cache of the users resolved from access tokens. Tokens are stored as
+// sha256 hashes and an entry never outlives the exp claim of its token.
+type userCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxTTL     time.Duration
+	lru        *list.List
+	entries    map[string]*list.Element
+	calls      map[string]*userCall
+	// generation is bumped by every invalidation. The tokens and users invalidated while a lookup is in
+	// flight are kept with the generation of their invalidation, so the lookup does not cache a stale user.
+	generation     uint64
+	invalidTokens  map[string]uint64
+	invalidUserIDs map[string]uint64
+}
+
+type userCacheEntry struct {
+	key       string
+	user      *User
+	expiresAt time.Time
+}
+
+// userCall is an in flight lookup shared by the concurrent callers of the same token. It runs with a
+// context no single caller can cancel, and is only canceled when every caller has given up.
+type userCall struct {
+	done       chan struct{}
+	user       *User
+	err        error
+	generation uint64
+	waiters    int
+	cancel     context.CancelFunc
+}
+
+func newUserCache(maxEntries int, maxTTL time.Duration) *userCache {
+	return &userCache{
+		maxEntries:     maxEntries,
+		maxTTL:         maxTTL,
+		lru:            list.New(),
+		entries:        make(map[string]*list.Element),
+		calls:          make(map[string]*userCall),
+		invalidTokens:  make(map[string]uint64),
+		invalidUserIDs: make(map[string]uint64),
+	}
+}
+
+func userCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// user returns the cached user of the token, or resolves it with fetch. Concurrent calls for the same
+// token share a single fetch, and each caller stops waiting when its own ctx is done.
+func (c *userCache) user(ctx context.Context, token string, fetch func(ctx context.Context) (*User, error)) (*User, error) {
+	key := userCacheKey(token)
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*userCacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			c.lru.MoveToFront(elem)
+			c.mu.Unlock()
+			return copyUser(entry.user), nil
+		}
+		c.remove(elem)
+	}
+	// A call every caller has given up is canceled, so it is not joined.
+	call, ok := c.calls[key]
+	if !ok || call.waiters == 0 {
+		call = c.start(ctx, key, token, fetch)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		return copyUser(call.user), nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+		}
+		c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// start runs the fetch of the token in the background. It must be called with the lock held.
+func (c *userCache) start(ctx context.Context, key, token string, fetch func(ctx context.Context) (*User, error)) *userCall {
+	fetchCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+	call := &userCall{
+		done:       make(chan struct{}),
+		generation: c.generation,
+		cancel:     cancel,
+	}
+	c.calls[key] = call
+	go func() {
+		user, err := fetch(fetchCtx)
+		cancel()
+
+		c.mu.Lock()
+		call.user, call.err = user, err
+		if c.calls[key] == call {
+			delete(c.calls, key)
+		}
+		if err == nil && !c.invalidatedSince(key, user, call.generation) {
+			c.add(key, token, user)
+		}
+		if len(c.calls) == 0 {
+			// No lookup is in flight, so the invalidations no longer need to be remembered.
+			c.invalidTokens = make(map[string]uint64)
+			c.invalidUserIDs = make(map[string]uint64)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// invalidatedSince reports whether the token or the user was invalidated after the generation.
+func (c *userCache) invalidatedSince(key string, user *User, generation uint64) bool {
+	if c.invalidTokens[key] > generation {
+		return true
+	}
+	return user != nil && c.invalidUserIDs[user.ID] > generation
+}
+
+// add caches the user until the token expires. Tokens without a readable exp claim are not cached.
+func (c *userCache) add(key, token string, user *User) {
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := decodeJWTClaims(token, &claims); err != nil || claims.Exp == 0 {
+		return
+	}
+	expiresAt := time.Unix(claims.Exp, 0)
+	if c.maxTTL > 0 && time.Until(expiresAt) > c.maxTTL {
+		expiresAt = time.Now().Add(c.maxTTL)
+	}
+	if !time.Now().Before(expiresAt) {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	c.entries[key] = c.lru.PushFront(&userCacheEntry{key: key, user: user, expiresAt: expiresAt})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *userCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*userCacheEntry).key)
+}
+
+// invalidateToken removes the cached user of the token.
+func (c *userCache) invalidateToken(token string) {
+	if c == nil {
+		return
+	}
+	key := userCacheKey(token)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if len(c.calls) > 0 {
+		c.invalidTokens[key] = c.generation
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// invalidateUser removes every cached token of the user.
+func (c *userCache) invalidateUser(userID string) {
+	if c == nil || userID == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if len(c.calls) > 0 {
+		c.invalidUserIDs[userID] = c.generation
+	}
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*userCacheEntry).user.ID == userID {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+// invalidateSession removes every cached token of the user who owns the token, after a change of the
+// user or of its sessions made with the token.
+func (c *userCache) invalidateSession(token string) {
+	if c == nil {
+		return
+	}
+	c.invalidateToken(token)
+	var claims struct {
+		Sub string `json:"sub"`
+	}
+	if err := decodeJWTClaims(token, &claims); err == nil {
+		c.invalidateUser(claims.Sub)
+	}
+}
+
+// copyUser returns a shallow copy, so a caller changing the fields does not change the cached user.
+func copyUser(user *User) *User {
+	if user == nil {
+		return nil
+	}
+	u := *user
+	return &u
+}
+
+// detachedContext keeps the values of the parent but not its cancellation, like context.WithoutCancel of go 1.21.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
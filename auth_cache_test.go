@@ -0,0 +1,142 @@
+package supabase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testToken(t *testing.T, sub string) string {
+	t.Helper()
+	claims, err := json.Marshal(map[string]interface{}{
+		"sub": sub,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+}
+
+func TestUserCacheWaiterCancel(t *testing.T) {
+	cache := newUserCache(10, 0)
+	token := testToken(t, "user-1")
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var fetchErr error
+	fetch := func(ctx context.Context) (*User, error) {
+		close(started)
+		<-release
+		fetchErr = ctx.Err()
+		return &User{ID: "user-1"}, nil
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan error)
+	go func() {
+		_, err := cache.user(firstCtx, token, fetch)
+		firstDone <- err
+	}()
+	<-started
+
+	secondDone := make(chan *User)
+	go func() {
+		user, err := cache.user(context.Background(), token, fetch)
+		if err != nil {
+			t.Error(err)
+		}
+		secondDone <- user
+	}()
+	// Wait for the second caller to join the call before the first gives up.
+	for {
+		cache.mu.Lock()
+		waiters := cache.calls[userCacheKey(token)].waiters
+		cache.mu.Unlock()
+		if waiters == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelFirst()
+	if err := <-firstDone; !errors.Is(err, context.Canceled) {
+		t.Fatalf("first caller got %v, want context.Canceled", err)
+	}
+	close(release)
+	if user := <-secondDone; user == nil || user.ID != "user-1" {
+		t.Fatalf("second caller got %v", user)
+	}
+	if fetchErr != nil {
+		t.Fatalf("shared fetch was canceled with %v", fetchErr)
+	}
+}
+
+func TestUserCacheInvalidateInFlight(t *testing.T) {
+	cache := newUserCache(10, 0)
+	token := testToken(t, "user-1")
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var calls int
+	var mu sync.Mutex
+	fetch := func(ctx context.Context) (*User, error) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n == 1 {
+			close(started)
+			<-release
+			return &User{ID: "user-1", Email: "old@example.com"}, nil
+		}
+		return &User{ID: "user-1", Email: "new@example.com"}, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := cache.user(context.Background(), token, fetch); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+	cache.invalidateUser("user-1")
+	close(release)
+	<-done
+
+	user, err := cache.user(context.Background(), token, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "new@example.com" {
+		t.Fatalf("got %s, the user fetched before the invalidation was cached", user.Email)
+	}
+}
+
+func TestUserCacheInvalidateSession(t *testing.T) {
+	cache := newUserCache(10, 0)
+	first, second := testToken(t, "user-1"), testToken(t, "user-1")+"x"
+	for _, token := range []string{first, second} {
+		if _, err := cache.user(context.Background(), token, func(ctx context.Context) (*User, error) {
+			return &User{ID: "user-1"}, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := cache.lruLen(); n != 2 {
+		t.Fatalf("got %d cached users, want 2", n)
+	}
+	cache.invalidateSession(first)
+	if n := cache.lruLen(); n != 0 {
+		t.Fatalf("got %d cached users, want 0", n)
+	}
+}
+
+func (c *userCache) lruLen() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
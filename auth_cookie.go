@@ -0,0 +1,229 @@
+package supabase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookie format refer from https://github.com/supabase/ssr/blob/main/src/utils/chunker.ts
+const (
+	cookieNameFormat    = "sb-%s-auth-token"
+	cookieBase64Prefix  = "base64-"
+	cookieMaxChunkSize  = 3180
+	cookieDefaultMaxAge = 400 * 24 * 60 * 60
+	// cookieRefreshMargin refreshes a session which expires within this duration.
+	cookieRefreshMargin = 60 * time.Second
+)
+
+// CookieOptions are the attributes of the session cookies. The defaults match @supabase/ssr, where
+// HttpOnly is false so the browser client can read the same session.
+type CookieOptions struct {
+	Domain   string
+	Path     string
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// CookieSession reads and writes sessions in the `sb-<project ref>-auth-token` cookies used by @supabase/ssr,
+// so a Go server shares the login with a browser or Next.js frontend of the same project.
+type CookieSession struct {
+	name    string
+	options CookieOptions
+}
+
+type CookieOption func(c *CookieSession)
+
+// WithCookieName overrides the cookie name, e.g. when the frontend sets a custom storageKey.
+func WithCookieName(name string) CookieOption {
+	return func(c *CookieSession) {
+		c.name = name
+	}
+}
+
+// WithCookieOptions overrides the attributes of the session cookies.
+func WithCookieOptions(options CookieOptions) CookieOption {
+	return func(c *CookieSession) {
+		c.options = options
+	}
+}
+
+func NewCookieSession(projectRef string, options ...CookieOption) *CookieSession {
+	impl := &CookieSession{
+		name: fmt.Sprintf(cookieNameFormat, projectRef),
+		options: CookieOptions{
+			Path:     "/",
+			MaxAge:   cookieDefaultMaxAge,
+			Secure:   true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+	for _, opt := range options {
+		opt(impl)
+	}
+	return impl
+}
+
+// Name returns the name of the session cookie. Chunks are named with a `.0`, `.1`, ... suffix.
+func (c *CookieSession) Name() string {
+	return c.name
+}
+
+// Session reassembles and decodes the session stored in the request cookies.
+// ErrNoSessionCookie is returned when the request has no session cookie.
+func (c *CookieSession) Session(r *http.Request) (*AuthDetailResp, error) {
+	value := c.readChunks(r)
+	if value == "" {
+		return nil, ErrNoSessionCookie
+	}
+	var raw []byte
+	if strings.HasPrefix(value, cookieBase64Prefix) {
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimPrefix(value, cookieBase64Prefix), "="))
+		if err != nil {
+			logger.Error("failed in decode session cookie with err: %s", err)
+			return nil, err
+		}
+		raw = decoded
+	} else {
+		// Older versions of @supabase/ssr store the json url encoded.
+		unescaped, err := url.PathUnescape(value)
+		if err != nil {
+			logger.Error("failed in unescape session cookie with err: %s", err)
+			return nil, err
+		}
+		raw = []byte(unescaped)
+	}
+	var session *AuthDetailResp
+	if err := json.Unmarshal(raw, &session); err != nil {
+		logger.Error("failed in unmarshal session cookie json with err: %s", err)
+		return nil, err
+	}
+	return session, nil
+}
+
+func (c *CookieSession) readChunks(r *http.Request) string {
+	if cookie, err := r.Cookie(c.name); err == nil {
+		return cookie.Value
+	}
+	var value strings.Builder
+	for i := 0; ; i++ {
+		cookie, err := r.Cookie(c.chunkName(i))
+		if err != nil {
+			break
+		}
+		value.WriteString(cookie.Value)
+	}
+	return value.String()
+}
+
+func (c *CookieSession) chunkName(i int) string {
+	return c.name + "." + strconv.Itoa(i)
+}
+
+// SetSession writes the session into the response cookies, split into chunks when it is too large for
+// one cookie. Cookies of the request which are no longer used by the new session are removed.
+func (c *CookieSession) SetSession(w http.ResponseWriter, r *http.Request, session *AuthDetailResp) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		logger.Error("failed in marshal session cookie with err: %s", err)
+		return err
+	}
+	value := cookieBase64Prefix + base64.RawURLEncoding.EncodeToString(raw)
+	if len(value) <= cookieMaxChunkSize {
+		http.SetCookie(w, c.cookie(c.name, value, c.options.MaxAge))
+		c.removeStale(w, r, map[string]bool{c.name: true})
+		return nil
+	}
+	written := make(map[string]bool)
+	for i := 0; len(value) > 0; i++ {
+		size := min(cookieMaxChunkSize, len(value))
+		name := c.chunkName(i)
+		http.SetCookie(w, c.cookie(name, value[:size], c.options.MaxAge))
+		written[name] = true
+		value = value[size:]
+	}
+	c.removeStale(w, r, written)
+	return nil
+}
+
+// Clear removes every session cookie of the request.
+func (c *CookieSession) Clear(w http.ResponseWriter, r *http.Request) {
+	c.removeStale(w, r, nil)
+}
+
+func (c *CookieSession) removeStale(w http.ResponseWriter, r *http.Request, keep map[string]bool) {
+	for _, cookie := range r.Cookies() {
+		if keep[cookie.Name] || !c.isSessionCookie(cookie.Name) {
+			continue
+		}
+		http.SetCookie(w, c.cookie(cookie.Name, "", -1))
+	}
+}
+
+func (c *CookieSession) isSessionCookie(name string) bool {
+	if name == c.name {
+		return true
+	}
+	suffix, found := strings.CutPrefix(name, c.name+".")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+func (c *CookieSession) cookie(name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     c.options.Path,
+		Domain:   c.options.Domain,
+		MaxAge:   maxAge,
+		Secure:   c.options.Secure,
+		HttpOnly: c.options.HttpOnly,
+		SameSite: c.options.SameSite,
+	}
+}
+
+// Refresh returns the session of the request. A session which is expired or about to expire is refreshed
+// and the refreshed session is written back to the response cookies.
+func (c *CookieSession) Refresh(ctx context.Context, auth authAPI, w http.ResponseWriter, r *http.Request) (*AuthDetailResp, error) {
+	session, err := c.Session(r)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Unix(int64(session.ExpiresAt), 0)
+	if time.Until(expiresAt) > cookieRefreshMargin {
+		return session, nil
+	}
+	session, err = auth.RefreshToken(ctx, session.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.SetSession(w, r, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// SignOut signs out the session of the request and clears the session cookies. The cookies are
+// cleared even when the sign out request fails, so the user is never stuck with a dead session.
+func (c *CookieSession) SignOut(ctx context.Context, auth authAPI, w http.ResponseWriter, r *http.Request, scope ...SignOutScope) error {
+	session, err := c.Session(r)
+	if err == ErrNoSessionCookie {
+		return nil
+	}
+	defer c.Clear(w, r)
+	if err != nil {
+		return err
+	}
+	return auth.SignOut(ctx, session.AccessToken, scope...)
+}
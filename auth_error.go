@@ -0,0 +1,146 @@
+package supabase
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Error codes refer from https://supabase.com/docs/guides/auth/debugging/error-codes
+// Use them with errors.Is, e.g. errors.Is(err, ErrInvalidCredentials). GoTrue servers older than the
+// error_code field are matched by their messages, for the codes listed in legacyErrorCodes.
+var (
+	ErrBadCodeVerifier            = &AuthError{Code: "bad_code_verifier"}
+	ErrBadJWT                     = &AuthError{Code: "bad_jwt"}
+	ErrCaptchaFailed              = &AuthError{Code: "captcha_failed"}
+	ErrEmailExists                = &AuthError{Code: "email_exists"}
+	ErrEmailNotConfirmed          = &AuthError{Code: "email_not_confirmed"}
+	ErrIdentityAlreadyExists      = &AuthError{Code: "identity_already_exists"}
+	ErrIdentityNotFound           = &AuthError{Code: "identity_not_found"}
+	ErrInvalidCredentials         = &AuthError{Code: "invalid_credentials"}
+	ErrMFAVerificationFailed      = &AuthError{Code: "mfa_verification_failed"}
+	ErrNotAdmin                   = &AuthError{Code: "not_admin"}
+	ErrOTPExpired                 = &AuthError{Code: "otp_expired"}
+	ErrOverEmailSendRateLimit     = &AuthError{Code: "over_email_send_rate_limit"}
+	ErrOverRequestRateLimit       = &AuthError{Code: "over_request_rate_limit"}
+	ErrOverSMSSendRateLimit       = &AuthError{Code: "over_sms_send_rate_limit"}
+	ErrPhoneNotConfirmed          = &AuthError{Code: "phone_not_confirmed"}
+	ErrReauthenticationNeeded     = &AuthError{Code: "reauthentication_needed"}
+	ErrRefreshTokenAlreadyUsed    = &AuthError{Code: "refresh_token_already_used"}
+	ErrRefreshTokenNotFound       = &AuthError{Code: "refresh_token_not_found"}
+	ErrSessionExpired             = &AuthError{Code: "session_expired"}
+	ErrSessionNotFound            = &AuthError{Code: "session_not_found"}
+	ErrSignupDisabled             = &AuthError{Code: "signup_disabled"}
+	ErrSingleIdentityNotDeletable = &AuthError{Code: "single_identity_not_deletable"}
+	ErrUserAlreadyExists          = &AuthError{Code: "user_already_exists"}
+	ErrUserBanned                 = &AuthError{Code: "user_banned"}
+	ErrUserNotFound               = &AuthError{Code: "user_not_found"}
+	ErrValidationFailed           = &AuthError{Code: "validation_failed"}
+	ErrWeakPassword               = &AuthError{Code: "weak_password"}
+)
+
+// AuthError is the error returned by the auth api. Code holds the error_code of GoTrue and
+// Reasons holds why a password is rejected when Code is weak_password.
+type AuthError struct {
+	Code           string   `json:"error_code"`
+	HTTPStatusCode int      `json:"-"`
+	Message        string   `json:"message"`
+	Reasons        []string `json:"reasons,omitempty"`
+}
+
+func (e *AuthError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *AuthError) StatusCode() int {
+	return e.HTTPStatusCode
+}
+
+// Is reports whether target is an AuthError with the same code, so the sentinel errors match with errors.Is.
+func (e *AuthError) Is(target error) bool {
+	t, ok := target.(*AuthError)
+	if !ok {
+		return false
+	}
+	return t.Code != "" && t.Code == e.Code
+}
+
+// legacyErrorCodes maps the message prefixes of GoTrue servers without error_code to the current codes.
+var legacyErrorCodes = []struct {
+	prefix string
+	code   string
+}{
+	{prefix: "invalid login credentials", code: "invalid_credentials"},
+	{prefix: "email not confirmed", code: "email_not_confirmed"},
+	{prefix: "phone not confirmed", code: "phone_not_confirmed"},
+	{prefix: "invalid refresh token: refresh token not found", code: "refresh_token_not_found"},
+	{prefix: "invalid refresh token: already used", code: "refresh_token_already_used"},
+	{prefix: "user already registered", code: "user_already_exists"},
+	{prefix: "a user with this email address has already been registered", code: "email_exists"},
+	{prefix: "user not found", code: "user_not_found"},
+	{prefix: "password should be", code: "weak_password"},
+	{prefix: "signups not allowed", code: "signup_disabled"},
+	{prefix: "email rate limit exceeded", code: "over_email_send_rate_limit"},
+	{prefix: "sms rate limit exceeded", code: "over_sms_send_rate_limit"},
+	{prefix: "token has expired or is invalid", code: "otp_expired"},
+	{prefix: "email link is invalid or has expired", code: "otp_expired"},
+	{prefix: "user is banned", code: "user_banned"},
+	{prefix: "code challenge does not match previously saved code verifier", code: "bad_code_verifier"},
+}
+
+func legacyErrorCode(message string) string {
+	message = strings.ToLower(message)
+	for _, legacy := range legacyErrorCodes {
+		if strings.HasPrefix(message, legacy.prefix) {
+			return legacy.code
+		}
+	}
+	return ""
+}
+
+// authErrorBody covers the error formats of GoTrue, which changed between api versions.
+type authErrorBody struct {
+	ErrorCode        string `json:"error_code"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Msg              string `json:"msg"`
+	Message          string `json:"message"`
+	WeakPassword     *struct {
+		Reasons []string `json:"reasons"`
+	} `json:"weak_password"`
+}
+
+// authError parses the response body of a failed auth request into an AuthError.
+func authError(body []byte, statusCode int) Exception {
+	authErr := &AuthError{
+		HTTPStatusCode: statusCode,
+	}
+	var errBody authErrorBody
+	if err := json.Unmarshal(body, &errBody); err != nil {
+		authErr.Message = string(body)
+		return authErr
+	}
+	for _, message := range []string{errBody.Msg, errBody.ErrorDescription, errBody.Message, errBody.Error} {
+		if message != "" {
+			authErr.Message = message
+			break
+		}
+	}
+	authErr.Code = errBody.ErrorCode
+	if authErr.Code == "" {
+		authErr.Code = legacyErrorCode(authErr.Message)
+	}
+	if authErr.Code == "" {
+		// The oauth error of older servers, e.g. invalid_grant, which matches no sentinel.
+		authErr.Code = errBody.Error
+	}
+	if errBody.WeakPassword != nil {
+		authErr.Reasons = errBody.WeakPassword.Reasons
+		if authErr.Code == "" {
+			authErr.Code = ErrWeakPassword.Code
+		}
+	}
+	return authErr
+}
@@ -0,0 +1,122 @@
+package supabase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAuthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		code     string
+		message  string
+		sentinel error
+	}{
+		{
+			name:     "error code",
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"error_code":"invalid_credentials","msg":"Invalid login credentials"}`,
+			code:     "invalid_credentials",
+			message:  "Invalid login credentials",
+			sentinel: ErrInvalidCredentials,
+		},
+		{
+			name:     "legacy oauth error",
+			status:   http.StatusBadRequest,
+			body:     `{"error":"invalid_grant","error_description":"Invalid login credentials"}`,
+			code:     "invalid_credentials",
+			message:  "Invalid login credentials",
+			sentinel: ErrInvalidCredentials,
+		},
+		{
+			name:     "legacy email not confirmed",
+			status:   http.StatusBadRequest,
+			body:     `{"error":"invalid_grant","error_description":"Email not confirmed"}`,
+			code:     "email_not_confirmed",
+			message:  "Email not confirmed",
+			sentinel: ErrEmailNotConfirmed,
+		},
+		{
+			name:     "legacy msg",
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"msg":"User already registered"}`,
+			code:     "user_already_exists",
+			message:  "User already registered",
+			sentinel: ErrUserAlreadyExists,
+		},
+		{
+			name:     "legacy refresh token",
+			status:   http.StatusBadRequest,
+			body:     `{"error":"invalid_grant","error_description":"Invalid Refresh Token: Already Used"}`,
+			code:     "refresh_token_already_used",
+			message:  "Invalid Refresh Token: Already Used",
+			sentinel: ErrRefreshTokenAlreadyUsed,
+		},
+		{
+			name:     "legacy code verifier",
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"msg":"code challenge does not match previously saved code verifier"}`,
+			code:     "bad_code_verifier",
+			message:  "code challenge does not match previously saved code verifier",
+			sentinel: ErrBadCodeVerifier,
+		},
+		{
+			name:    "unknown legacy oauth error",
+			status:  http.StatusBadRequest,
+			body:    `{"error":"invalid_request","error_description":"something else"}`,
+			code:    "invalid_request",
+			message: "something else",
+		},
+		{
+			name:     "weak password",
+			status:   http.StatusUnprocessableEntity,
+			body:     `{"code":422,"msg":"Password should contain a number","weak_password":{"reasons":["characters"]}}`,
+			code:     "weak_password",
+			message:  "Password should contain a number",
+			sentinel: ErrWeakPassword,
+		},
+		{
+			name:    "not json",
+			status:  http.StatusBadGateway,
+			body:    `bad gateway`,
+			message: "bad gateway",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := authError([]byte(tc.body), tc.status)
+			var authErr *AuthError
+			if !errors.As(err, &authErr) {
+				t.Fatalf("got %T, want *AuthError", err)
+			}
+			if authErr.Code != tc.code || authErr.Message != tc.message || authErr.StatusCode() != tc.status {
+				t.Errorf("got %+v", authErr)
+			}
+			if tc.sentinel != nil {
+				wrapped := fmt.Errorf("sign in: %w", err)
+				if !errors.Is(wrapped, tc.sentinel) {
+					t.Errorf("errors.Is(%v, %v) = false", wrapped, tc.sentinel)
+				}
+				var wrappedErr *AuthError
+				if !errors.As(wrapped, &wrappedErr) || wrappedErr.StatusCode() != tc.status {
+					t.Errorf("errors.As through wrapping = %+v", wrappedErr)
+				}
+			}
+			if errors.Is(err, ErrUserBanned) {
+				t.Errorf("%v matches an unrelated sentinel", err)
+			}
+		})
+	}
+}
+
+func TestAuthErrorWeakPasswordReasons(t *testing.T) {
+	err := authError([]byte(`{"error_code":"weak_password","msg":"weak","weak_password":{"reasons":["length","pwned"]}}`), http.StatusUnprocessableEntity)
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || len(authErr.Reasons) != 2 || authErr.Reasons[1] != "pwned" {
+		t.Errorf("got %+v", err)
+	}
+}
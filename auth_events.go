@@ -0,0 +1,86 @@
+package supabase
+
+import "sync"
+
+// authEventBufferSize is the number of events queued per subscriber before new events are dropped.
+const authEventBufferSize = 64
+
+// AuthStateChangeFunc receives the auth events. Session is the session after the event; on
+// AuthChangeEventSignedOut it only carries the access token that was signed out, and on
+// AuthChangeEventUserUpdated it carries the access token used with the updated user.
+type AuthStateChangeFunc func(event AuthChangeEvent, session *AuthDetailResp)
+
+type authStateChange struct {
+	event   AuthChangeEvent
+	session *AuthDetailResp
+}
+
+// AuthSubscription is returned by OnAuthStateChange. Call Unsubscribe to stop receiving events.
+type AuthSubscription struct {
+	id      uint64
+	events  *authEvents
+	changes chan authStateChange
+	once    sync.Once
+}
+
+// Unsubscribe stops the delivery of events. Events already queued are still delivered.
+func (s *AuthSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.events.unsubscribe(s.id)
+		close(s.changes)
+	})
+}
+
+type authEvents struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers map[uint64]*AuthSubscription
+}
+
+func newAuthEvents() *authEvents {
+	return &authEvents{
+		subscribers: make(map[uint64]*AuthSubscription),
+	}
+}
+
+func (e *authEvents) subscribe(callback AuthStateChangeFunc) *AuthSubscription {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextID++
+	sub := &AuthSubscription{
+		id:      e.nextID,
+		events:  e,
+		changes: make(chan authStateChange, authEventBufferSize),
+	}
+	e.subscribers[sub.id] = sub
+	// Every subscriber has its own goroutine so a slow callback never blocks the auth calls or other subscribers.
+	go func() {
+		for change := range sub.changes {
+			callback(change.event, change.session)
+		}
+	}()
+	return sub
+}
+
+func (e *authEvents) unsubscribe(id uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.subscribers, id)
+}
+
+// emit queues the event to every subscriber without blocking. The event is dropped for a
+// subscriber whose queue is full.
+func (e *authEvents) emit(event AuthChangeEvent, session *AuthDetailResp) {
+	if e == nil {
+		return
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, sub := range e.subscribers {
+		select {
+		case sub.changes <- authStateChange{event: event, session: session}:
+		default:
+			logger.Warn("dropped %s auth event due to subscriber %d is full", event, sub.id)
+		}
+	}
+}
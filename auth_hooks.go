@@ -0,0 +1,250 @@
+package supabase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Auth hooks refer from https://supabase.com/docs/guides/auth/auth-hooks
+const (
+	webhookIDHeader        = "webhook-id"
+	webhookTimestampHeader = "webhook-timestamp"
+	webhookSignatureHeader = "webhook-signature"
+	webhookSecretPrefix    = "whsec_"
+	webhookSignatureScheme = "v1"
+	webhookTolerance       = 5 * time.Minute
+	hookMaxBodySize        = 1 << 20
+)
+
+var (
+	ErrWebhookSecret    = errors.New("webhook secret must be in the format v1,whsec_<base64 secret>")
+	ErrWebhookHeader    = errors.New("webhook-id, webhook-timestamp and webhook-signature headers are mandatory")
+	ErrWebhookTimestamp = errors.New("webhook timestamp is outside of the tolerance")
+	ErrWebhookSignature = errors.New("webhook signature does not match")
+)
+
+// WebhookVerifier verifies the Standard Webhooks signature (https://www.standardwebhooks.com) of the auth hook requests.
+type WebhookVerifier struct {
+	secret    []byte
+	tolerance time.Duration
+}
+
+// NewWebhookVerifier creates a verifier from the hook secret shown in the dashboard, e.g. `v1,whsec_abc...`.
+func NewWebhookVerifier(secret string) (*WebhookVerifier, error) {
+	secret = strings.TrimPrefix(secret, webhookSignatureScheme+",")
+	secret = strings.TrimPrefix(secret, webhookSecretPrefix)
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrWebhookSecret
+	}
+	return &WebhookVerifier{
+		secret:    key,
+		tolerance: webhookTolerance,
+	}, nil
+}
+
+// Verify checks that the body is signed with the secret and that the timestamp is recent, to prevent replays.
+func (v *WebhookVerifier) Verify(header http.Header, body []byte) error {
+	id := header.Get(webhookIDHeader)
+	timestamp := header.Get(webhookTimestampHeader)
+	signatures := header.Get(webhookSignatureHeader)
+	if id == "" || timestamp == "" || signatures == "" {
+		return ErrWebhookHeader
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookTimestamp
+	}
+	sentAt := time.Unix(unix, 0)
+	if time.Since(sentAt) > v.tolerance || time.Until(sentAt) > v.tolerance {
+		return ErrWebhookTimestamp
+	}
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	// The header holds space delimited signatures, e.g. `v1,<base64> v1,<base64>` while a secret is rotated.
+	for _, signature := range strings.Fields(signatures) {
+		scheme, value, found := strings.Cut(signature, ",")
+		if !found || scheme != webhookSignatureScheme {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrWebhookSignature
+}
+
+// HookError is returned by a hook to reject the request. HTTPCode and Message are passed to the user by GoTrue.
+type HookError struct {
+	HTTPCode int    `json:"http_code"`
+	Message  string `json:"message"`
+}
+
+func (e *HookError) Error() string {
+	return e.Message
+}
+
+type hookErrorResp struct {
+	Error *HookError `json:"error"`
+}
+
+type CustomAccessTokenHookInput struct {
+	UserID               string                 `json:"user_id"`
+	Claims               map[string]interface{} `json:"claims"`
+	AuthenticationMethod string                 `json:"authentication_method"`
+}
+
+type CustomAccessTokenHookOutput struct {
+	Claims map[string]interface{} `json:"claims"`
+}
+
+type EmailData struct {
+	Token           string `json:"token"`
+	TokenHash       string `json:"token_hash"`
+	RedirectTo      string `json:"redirect_to"`
+	EmailActionType string `json:"email_action_type"`
+	SiteURL         string `json:"site_url"`
+	TokenNew        string `json:"token_new"`
+	TokenHashNew    string `json:"token_hash_new"`
+}
+
+type SendEmailHookInput struct {
+	User      User      `json:"user"`
+	EmailData EmailData `json:"email_data"`
+}
+
+type SendEmailHookOutput struct{}
+
+type SMSData struct {
+	OTP string `json:"otp"`
+}
+
+type SendSMSHookInput struct {
+	User User    `json:"user"`
+	SMS  SMSData `json:"sms"`
+}
+
+type SendSMSHookOutput struct{}
+
+type MFAVerificationAttemptHookInput struct {
+	FactorID   string `json:"factor_id"`
+	FactorType string `json:"factor_type"`
+	UserID     string `json:"user_id"`
+	Valid      bool   `json:"valid"`
+}
+
+type MFAVerificationAttemptHookOutput struct {
+	// Decision takes one of the HookDecision values.
+	Decision string `json:"decision"`
+	Message  string `json:"message,omitempty"`
+}
+
+type PasswordVerificationAttemptHookInput struct {
+	UserID string `json:"user_id"`
+	Valid  bool   `json:"valid"`
+}
+
+type PasswordVerificationAttemptHookOutput struct {
+	// Decision takes one of the HookDecision values.
+	Decision         string `json:"decision"`
+	Message          string `json:"message,omitempty"`
+	ShouldLogoutUser bool   `json:"should_logout_user,omitempty"`
+}
+
+// HookHandler adapts a hook function to an http.Handler. The request is verified with the verifier and
+// decoded into In, and the output of the hook is encoded as json. An error of type *HookError is sent
+// to GoTrue with its HTTPCode, any other error is sent as 500.
+func HookHandler[In, Out any](verifier *WebhookVerifier, hook func(ctx context.Context, input In) (Out, error)) http.Handler {
+	if verifier == nil {
+		panic("webhook verifier is mandatory for auth hook handler")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeHookError(w, &HookError{HTTPCode: http.StatusMethodNotAllowed, Message: "method not allowed"})
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, hookMaxBodySize))
+		if err != nil {
+			logger.Error("failed in read hook body with err: %s", err)
+			writeHookError(w, &HookError{HTTPCode: http.StatusBadRequest, Message: "invalid body"})
+			return
+		}
+		if err = verifier.Verify(r.Header, body); err != nil {
+			logger.Warn("failed in verify hook with err: %s", err)
+			writeHookError(w, &HookError{HTTPCode: http.StatusUnauthorized, Message: err.Error()})
+			return
+		}
+		var input In
+		if err = json.Unmarshal(body, &input); err != nil {
+			logger.Error("failed in unmarshal hook json with err: %s", err)
+			writeHookError(w, &HookError{HTTPCode: http.StatusBadRequest, Message: "invalid json"})
+			return
+		}
+		output, err := hook(r.Context(), input)
+		if err != nil {
+			var hookErr *HookError
+			if !errors.As(err, &hookErr) {
+				logger.Error("failed in hook with err: %s", err)
+				hookErr = &HookError{HTTPCode: http.StatusInternalServerError, Message: "internal server error"}
+			}
+			writeHookError(w, hookErr)
+			return
+		}
+		w.Header().Set(headerContentType, applicationJSON)
+		if err = json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("failed in encode hook output with err: %s", err)
+		}
+	})
+}
+
+func writeHookError(w http.ResponseWriter, hookErr *HookError) {
+	// A hook error without a valid status is still a rejection, so it is sent as 500 instead of panicking.
+	if hookErr.HTTPCode < http.StatusBadRequest || hookErr.HTTPCode > 599 {
+		hookErr = &HookError{HTTPCode: http.StatusInternalServerError, Message: hookErr.Message}
+	}
+	w.Header().Set(headerContentType, applicationJSON)
+	w.WriteHeader(hookErr.HTTPCode)
+	if err := json.NewEncoder(w).Encode(hookErrorResp{Error: hookErr}); err != nil {
+		logger.Error("failed in encode hook error with err: %s", err)
+	}
+}
+
+// CustomAccessTokenHookHandler serves the custom access token hook, which returns the claims of the access token.
+func CustomAccessTokenHookHandler(verifier *WebhookVerifier, hook func(ctx context.Context, input CustomAccessTokenHookInput) (CustomAccessTokenHookOutput, error)) http.Handler {
+	return HookHandler(verifier, hook)
+}
+
+// SendEmailHookHandler serves the send email hook, which replaces the built-in email sender.
+func SendEmailHookHandler(verifier *WebhookVerifier, hook func(ctx context.Context, input SendEmailHookInput) (SendEmailHookOutput, error)) http.Handler {
+	return HookHandler(verifier, hook)
+}
+
+// SendSMSHookHandler serves the send SMS hook, which replaces the built-in SMS provider.
+func SendSMSHookHandler(verifier *WebhookVerifier, hook func(ctx context.Context, input SendSMSHookInput) (SendSMSHookOutput, error)) http.Handler {
+	return HookHandler(verifier, hook)
+}
+
+// MFAVerificationAttemptHookHandler serves the MFA verification attempt hook, which decides whether a failed attempt is rejected.
+func MFAVerificationAttemptHookHandler(verifier *WebhookVerifier, hook func(ctx context.Context, input MFAVerificationAttemptHookInput) (MFAVerificationAttemptHookOutput, error)) http.Handler {
+	return HookHandler(verifier, hook)
+}
+
+// PasswordVerificationAttemptHookHandler serves the password verification attempt hook, which decides whether a failed attempt is rejected.
+func PasswordVerificationAttemptHookHandler(verifier *WebhookVerifier, hook func(ctx context.Context, input PasswordVerificationAttemptHookInput) (PasswordVerificationAttemptHookOutput, error)) http.Handler {
+	return HookHandler(verifier, hook)
+}
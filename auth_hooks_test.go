@@ -0,0 +1,216 @@
+package supabase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testHookKey    = []byte("test-hook-secret")
+	testHookSecret = "v1,whsec_" + base64.StdEncoding.EncodeToString(testHookKey)
+)
+
+func signHook(key []byte, id string, timestamp time.Time, body string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + strconv.FormatInt(timestamp.Unix(), 10) + "." + body))
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hookHeader(id string, timestamp time.Time, signature string) http.Header {
+	header := make(http.Header)
+	header.Set(webhookIDHeader, id)
+	header.Set(webhookTimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(webhookSignatureHeader, signature)
+	return header
+}
+
+func TestNewWebhookVerifier(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		err    error
+	}{
+		{name: "with scheme", secret: testHookSecret},
+		{name: "without scheme", secret: "whsec_" + base64.StdEncoding.EncodeToString(testHookKey)},
+		{name: "bad base64", secret: "v1,whsec_not*base64!", err: ErrWebhookSecret},
+		{name: "empty", secret: "v1,whsec_", err: ErrWebhookSecret},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewWebhookVerifier(tc.secret); !errors.Is(err, tc.err) {
+				t.Errorf("got %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWebhookVerify(t *testing.T) {
+	verifier, err := NewWebhookVerifier(testHookSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	body := `{"user_id":"user-1"}`
+	rotatedKey := []byte("old-hook-secret")
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		err    error
+	}{
+		{
+			name:   "valid signature",
+			header: hookHeader("msg_1", now, signHook(testHookKey, "msg_1", now, body)),
+			body:   body,
+		},
+		{
+			name:   "tampered body",
+			header: hookHeader("msg_1", now, signHook(testHookKey, "msg_1", now, body)),
+			body:   `{"user_id":"user-2"}`,
+			err:    ErrWebhookSignature,
+		},
+		{
+			name:   "tampered id",
+			header: hookHeader("msg_2", now, signHook(testHookKey, "msg_1", now, body)),
+			body:   body,
+			err:    ErrWebhookSignature,
+		},
+		{
+			name:   "wrong secret",
+			header: hookHeader("msg_1", now, signHook(rotatedKey, "msg_1", now, body)),
+			body:   body,
+			err:    ErrWebhookSignature,
+		},
+		{
+			name:   "timestamp in the past",
+			header: hookHeader("msg_1", now.Add(-6*time.Minute), signHook(testHookKey, "msg_1", now.Add(-6*time.Minute), body)),
+			body:   body,
+			err:    ErrWebhookTimestamp,
+		},
+		{
+			name:   "timestamp in the future",
+			header: hookHeader("msg_1", now.Add(6*time.Minute), signHook(testHookKey, "msg_1", now.Add(6*time.Minute), body)),
+			body:   body,
+			err:    ErrWebhookTimestamp,
+		},
+		{
+			name:   "rotated secret matches the second signature",
+			header: hookHeader("msg_1", now, signHook(rotatedKey, "msg_1", now, body)+" "+signHook(testHookKey, "msg_1", now, body)),
+			body:   body,
+		},
+		{
+			name:   "other scheme is ignored",
+			header: hookHeader("msg_1", now, strings.Replace(signHook(testHookKey, "msg_1", now, body), "v1,", "v1a,", 1)),
+			body:   body,
+			err:    ErrWebhookSignature,
+		},
+		{
+			name:   "missing header",
+			header: hookHeader("", now, signHook(testHookKey, "", now, body)),
+			body:   body,
+			err:    ErrWebhookHeader,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := verifier.Verify(tc.header, []byte(tc.body)); !errors.Is(err, tc.err) {
+				t.Errorf("got %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestHookHandler(t *testing.T) {
+	verifier, err := NewWebhookVerifier(testHookSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := CustomAccessTokenHookHandler(verifier, func(ctx context.Context, input CustomAccessTokenHookInput) (CustomAccessTokenHookOutput, error) {
+		switch input.UserID {
+		case "banned":
+			return CustomAccessTokenHookOutput{}, &HookError{HTTPCode: http.StatusForbidden, Message: "user is banned"}
+		case "no-status":
+			return CustomAccessTokenHookOutput{}, &HookError{Message: "rejected"}
+		case "fail":
+			return CustomAccessTokenHookOutput{}, errors.New("database is down")
+		}
+		input.Claims["plan"] = "pro"
+		return CustomAccessTokenHookOutput{Claims: input.Claims}, nil
+	})
+	now := time.Now()
+	signed := func(body string) http.Header {
+		return hookHeader("msg_1", now, signHook(testHookKey, "msg_1", now, body))
+	}
+	tests := []struct {
+		name    string
+		method  string
+		header  func(body string) http.Header
+		body    string
+		status  int
+		message string
+	}{
+		{name: "success", body: `{"user_id":"user-1","claims":{"sub":"user-1"}}`, status: http.StatusOK},
+		{name: "method not allowed", method: http.MethodGet, body: `{}`, status: http.StatusMethodNotAllowed, message: "method not allowed"},
+		{
+			name:    "bad signature",
+			header:  func(body string) http.Header { return signed(body + " ") },
+			body:    `{"user_id":"user-1"}`,
+			status:  http.StatusUnauthorized,
+			message: ErrWebhookSignature.Error(),
+		},
+		{
+			name:    "missing headers",
+			header:  func(string) http.Header { return http.Header{} },
+			body:    `{"user_id":"user-1"}`,
+			status:  http.StatusUnauthorized,
+			message: ErrWebhookHeader.Error(),
+		},
+		{name: "invalid json", body: `{"user_id":`, status: http.StatusBadRequest, message: "invalid json"},
+		{name: "hook error", body: `{"user_id":"banned"}`, status: http.StatusForbidden, message: "user is banned"},
+		{name: "hook error without status", body: `{"user_id":"no-status"}`, status: http.StatusInternalServerError, message: "rejected"},
+		{name: "other error", body: `{"user_id":"fail"}`, status: http.StatusInternalServerError, message: "internal server error"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			header := signed
+			if tc.header != nil {
+				header = tc.header
+			}
+			req := httptest.NewRequest(method, "/hooks/access-token", strings.NewReader(tc.body))
+			req.Header = header(tc.body)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.status, w.Body.String())
+			}
+			if tc.status == http.StatusOK {
+				var output CustomAccessTokenHookOutput
+				if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil || output.Claims["plan"] != "pro" {
+					t.Errorf("output = %s", w.Body.String())
+				}
+				return
+			}
+			var resp hookErrorResp
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error == nil {
+				t.Fatalf("body = %s", w.Body.String())
+			}
+			if resp.Error.HTTPCode != tc.status || resp.Error.Message != tc.message {
+				t.Errorf("error = %+v, want %d %s", resp.Error, tc.status, tc.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type mfaAPI interface {
+	Enroll(ctx context.Context, token string, body MFAEnrollRequest) (*MFAEnrollResp, error)
+	Challenge(ctx context.Context, token string, body MFAChallengeRequest) (*MFAChallengeResp, error)
+	Verify(ctx context.Context, token string, body MFAVerifyRequest) (*AuthDetailResp, error)
+	ChallengeAndVerify(ctx context.Context, token string, body MFAChallengeAndVerifyRequest) (*AuthDetailResp, error)
+	Unenroll(ctx context.Context, token, factorID string) (*MFAUnenrollResp, error)
+	ListFactors(ctx context.Context, token string) (*MFAListFactorsResp, error)
+	GetAuthenticatorAssuranceLevel(ctx context.Context, token string) (*AuthenticatorAssuranceLevelResp, error)
+}
+
+// AuthMFA refer from https://github.com/supabase/gotrue-js/blob/master/src/GoTrueClient.ts
+type AuthMFA struct {
+	auth Auth
+}
+
+// MFA returns the multi-factor authentication API.
+func (i Auth) MFA() mfaAPI {
+	return &AuthMFA{auth: i}
+}
+
+// Enroll starts the enrollment of a factor. A TOTP factor returns the QR code, secret and uri to be
+// shown to the user. The factor stays unverified until Verify or ChallengeAndVerify succeeds.
+func (i AuthMFA) Enroll(ctx context.Context, token string, body MFAEnrollRequest) (*MFAEnrollResp, error) {
+	reqURL := fmt.Sprintf("%s/factors", i.auth.authHost)
+	httpResp, err := i.auth.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.auth.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in mfa enroll httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in mfa enroll due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var enroll *MFAEnrollResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &enroll)
+	if err != nil {
+		logger.Error("failed in unmarshal mfa enroll json with err: %s", err)
+		return nil, err
+	}
+	i.auth.userCache.invalidateSession(token)
+	return enroll, nil
+}
+
+// Challenge prepares a challenge used to verify that a user has access to a factor.
+func (i AuthMFA) Challenge(ctx context.Context, token string, body MFAChallengeRequest) (*MFAChallengeResp, error) {
+	reqURL := fmt.Sprintf("%s/factors/%s/challenge", i.auth.authHost, body.FactorID)
+	httpResp, err := i.auth.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.auth.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in mfa challenge httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in mfa challenge due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var challenge *MFAChallengeResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &challenge)
+	if err != nil {
+		logger.Error("failed in unmarshal mfa challenge json with err: %s", err)
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// Verify verifies a code against a challenge. The returned session is upgraded to aal2.
+func (i AuthMFA) Verify(ctx context.Context, token string, body MFAVerifyRequest) (*AuthDetailResp, error) {
+	reqURL := fmt.Sprintf("%s/factors/%s/verify", i.auth.authHost, body.FactorID)
+	httpResp, err := i.auth.httpClient.Call(ctx, reqURL, http.MethodPost, body, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.auth.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in mfa verify httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in mfa verify due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var authDetail *AuthDetailResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &authDetail)
+	if err != nil {
+		logger.Error("failed in unmarshal Auth detail json with err: %s", err)
+		return nil, err
+	}
+	i.auth.userCache.invalidateSession(token)
+	i.auth.events.emit(AuthChangeEventMFAChallengeVerified, authDetail)
+	return authDetail, nil
+}
+
+// ChallengeAndVerify creates a challenge and immediately verifies the given code against it.
+func (i AuthMFA) ChallengeAndVerify(ctx context.Context, token string, body MFAChallengeAndVerifyRequest) (*AuthDetailResp, error) {
+	challenge, err := i.Challenge(ctx, token, MFAChallengeRequest{FactorID: body.FactorID})
+	if err != nil {
+		return nil, err
+	}
+	return i.Verify(ctx, token, MFAVerifyRequest{
+		FactorID:    body.FactorID,
+		ChallengeID: challenge.ID,
+		Code:        body.Code,
+	})
+}
+
+// Unenroll removes a factor. The session must be aal2 to unenroll a verified factor.
+func (i AuthMFA) Unenroll(ctx context.Context, token, factorID string) (*MFAUnenrollResp, error) {
+	reqURL := fmt.Sprintf("%s/factors/%s", i.auth.authHost, factorID)
+	httpResp, err := i.auth.httpClient.Call(ctx, reqURL, http.MethodDelete, nil, func(req *http.Request) {
+		req.Header.Set(authorizationHeader, i.auth.apiKey)
+		req.Header.Set(HeaderAuthorization.String(), fmt.Sprintf("%s %s", authPrefix, token))
+	})
+	if err != nil {
+		logger.Error("failed in mfa unenroll httpclient call with err: %s", err)
+		return nil, err
+	}
+	if !isHTTPSuccess(httpResp.StatusCode) {
+		logger.Warn("getting %d in mfa unenroll due to err: %s", httpResp.StatusCode, httpResp.Body.String())
+		return nil, authError(httpResp.Body.Bytes(), httpResp.StatusCode)
+	}
+	var unenroll *MFAUnenrollResp
+	err = json.Unmarshal(httpResp.Body.Bytes(), &unenroll)
+	if err != nil {
+		logger.Error("failed in unmarshal mfa unenroll json with err: %s", err)
+		return nil, err
+	}
+	i.auth.userCache.invalidateSession(token)
+	return unenroll, nil
+}
+
+// ListFactors returns the verified factors of the current user, grouped by factor type.
+func (i AuthMFA) ListFactors(ctx context.Context, token string) (*MFAListFactorsResp, error) {
+	user, err := i.auth.User(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	factors := &MFAListFactorsResp{}
+	for _, factor := range user.Factors {
+		if factor.Status != "verified" {
+			continue
+		}
+		factors.All = append(factors.All, factor)
+		switch factor.FactorType {
+		case FactorTypeTOTP.String():
+			factors.TOTP = append(factors.TOTP, factor)
+		case FactorTypePhone.String():
+			factors.Phone = append(factors.Phone, factor)
+		}
+	}
+	return factors, nil
+}
+
+// GetAuthenticatorAssuranceLevel returns the assurance level of the session from its aal and amr claims.
+// NextLevel is aal2 when the user has a verified factor, so the session can be upgraded with a challenge.
+func (i AuthMFA) GetAuthenticatorAssuranceLevel(ctx context.Context, token string) (*AuthenticatorAssuranceLevelResp, error) {
+	var claims struct {
+		AAL string     `json:"aal"`
+		AMR []AMREntry `json:"amr"`
+	}
+	if err := decodeJWTClaims(token, &claims); err != nil {
+		logger.Error("failed in decode jwt claims with err: %s", err)
+		return nil, err
+	}
+	factors, err := i.ListFactors(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	nextLevel := claims.AAL
+	if len(factors.All) > 0 {
+		nextLevel = AAL2.String()
+	}
+	return &AuthenticatorAssuranceLevelResp{
+		CurrentLevel:                 claims.AAL,
+		NextLevel:                    nextLevel,
+		CurrentAuthenticationMethods: claims.AMR,
+	}, nil
+}
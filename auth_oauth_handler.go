@@ -0,0 +1,187 @@
+package supabase
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	oauthFlowCookiePrefix = "sb-oauth-flow-"
+	oauthFlowCookieMaxAge = 10 * 60
+)
+
+// OAuthHandlerConfig configures the OAuth round trip of OAuthHandler.
+type OAuthHandlerConfig struct {
+	// CallbackURL is the absolute url the OAuthHandler is served on. It must be in the redirect urls of the project.
+	CallbackURL string
+	// SuccessURL is where the user is redirected after the session is stored.
+	SuccessURL string
+	// FailureURL is where the user is redirected with the `error` and `error_description` query parameters
+	// when the sign in fails. A 400 response is written instead when it is empty.
+	FailureURL string
+	// OnSession stores the session, e.g. with CookieSession.SetSession. The session holds the
+	// ProviderToken and ProviderRefreshToken when the provider returns them.
+	OnSession func(w http.ResponseWriter, r *http.Request, session *AuthDetailResp) error
+	// InsecureCookie allows the flow cookie to be sent over http, which is only meant for local development.
+	InsecureCookie bool
+}
+
+// OAuthHandler completes the OAuth sign in with the PKCE flow. SignIn redirects the user to the provider and
+// the handler itself serves the callback, where the code is exchanged for a session. The code verifier never
+// leaves an http only cookie, so a code injected into the callback by another site cannot be exchanged, and
+// the callback url is sent to GoTrue as is, so it matches the redirect urls of the project exactly. Each flow
+// has its own cookie named by a random state, so sign ins started in parallel tabs do not overwrite each other.
+type OAuthHandler struct {
+	auth   authAPI
+	config OAuthHandlerConfig
+}
+
+func NewOAuthHandler(auth authAPI, config OAuthHandlerConfig) *OAuthHandler {
+	return &OAuthHandler{
+		auth:   auth,
+		config: config,
+	}
+}
+
+// SignIn redirects the user to the authorize url of the provider set in body.
+func (h *OAuthHandler) SignIn(w http.ResponseWriter, r *http.Request, body OAuthSignInRequest) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		h.fail(w, r, "server_error", "failed to start sign in")
+		return
+	}
+	state, err := randomState()
+	if err != nil {
+		logger.Error("failed in generate oauth state with err: %s", err)
+		h.fail(w, r, "server_error", "failed to start sign in")
+		return
+	}
+	body.RedirectTo = h.config.CallbackURL
+	body.CodeChallenge = pkce.CodeChallenge
+	body.CodeChallengeMethod = pkce.CodeChallengeMethod
+	authURL, err := h.auth.SignInWithOAuth(r.Context(), body)
+	if err != nil {
+		h.fail(w, r, "server_error", "failed to start sign in")
+		return
+	}
+	value := strconv.FormatInt(time.Now().Unix(), 10) + "." + pkce.CodeVerifier
+	http.SetCookie(w, h.flowCookie(oauthFlowCookiePrefix+state, value, oauthFlowCookieMaxAge))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// ServeHTTP serves the callback of the OAuth round trip. The code is exchanged with the verifier of the
+// newest flow first; a verifier of another flow is rejected by GoTrue with bad_code_verifier and the next
+// flow is tried.
+func (h *OAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	if errCode := qs.Get("error"); errCode != "" {
+		h.fail(w, r, errCode, qs.Get("error_description"))
+		return
+	}
+	code := qs.Get("code")
+	if code == "" {
+		h.fail(w, r, "invalid_request", "code is missing")
+		return
+	}
+	flows := h.flows(r)
+	if len(flows) == 0 {
+		h.fail(w, r, "invalid_request", "sign in flow is not found or expired")
+		return
+	}
+	for _, flow := range flows {
+		session, err := h.auth.ExchangeCodeForSession(r.Context(), ExchangeCodeRequest{
+			AuthCode:     code,
+			CodeVerifier: flow.verifier,
+		})
+		if errors.Is(err, ErrBadCodeVerifier) {
+			continue
+		}
+		// The flow is single use, so its cookie is removed whatever the outcome of the exchange is.
+		http.SetCookie(w, h.flowCookie(flow.name, "", -1))
+		if err != nil {
+			h.fail(w, r, "invalid_grant", "failed to exchange code for session")
+			return
+		}
+		if h.config.OnSession != nil {
+			if err = h.config.OnSession(w, r, session); err != nil {
+				logger.Error("failed in store oauth session with err: %s", err)
+				h.fail(w, r, "server_error", "failed to store session")
+				return
+			}
+		}
+		http.Redirect(w, r, h.config.SuccessURL, http.StatusFound)
+		return
+	}
+	h.fail(w, r, "invalid_grant", "code does not belong to a sign in flow of this browser")
+}
+
+type oauthFlow struct {
+	name     string
+	issuedAt int64
+	verifier string
+}
+
+// flows returns the sign in flows of the request, newest first.
+func (h *OAuthHandler) flows(r *http.Request) []oauthFlow {
+	var flows []oauthFlow
+	for _, cookie := range r.Cookies() {
+		if !strings.HasPrefix(cookie.Name, oauthFlowCookiePrefix) {
+			continue
+		}
+		issuedAt, verifier, found := strings.Cut(cookie.Value, ".")
+		if !found || verifier == "" {
+			continue
+		}
+		unix, err := strconv.ParseInt(issuedAt, 10, 64)
+		if err != nil {
+			continue
+		}
+		flows = append(flows, oauthFlow{name: cookie.Name, issuedAt: unix, verifier: verifier})
+	}
+	sort.SliceStable(flows, func(i, j int) bool {
+		return flows[i].issuedAt > flows[j].issuedAt
+	})
+	return flows
+}
+
+func (h *OAuthHandler) fail(w http.ResponseWriter, r *http.Request, errCode, description string) {
+	logger.Warn("oauth sign in failed with %s: %s", errCode, description)
+	failureURL, err := url.Parse(h.config.FailureURL)
+	if h.config.FailureURL == "" || err != nil {
+		http.Error(w, description, http.StatusBadRequest)
+		return
+	}
+	qs := failureURL.Query()
+	qs.Set("error", errCode)
+	qs.Set("error_description", description)
+	failureURL.RawQuery = qs.Encode()
+	http.Redirect(w, r, failureURL.String(), http.StatusFound)
+}
+
+func (h *OAuthHandler) flowCookie(name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   !h.config.InsecureCookie,
+		HttpOnly: true,
+		// Lax lets the cookie be sent on the top level redirect back from the provider.
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
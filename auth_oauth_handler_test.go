@@ -0,0 +1,182 @@
+package supabase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testCallbackURL = "https://example.com/auth/callback"
+
+// fakeOAuth issues one code per sign in, which is only exchanged with the verifier of that sign in.
+type fakeOAuth struct {
+	authAPI
+	codes     map[string]string
+	exchanges []ExchangeCodeRequest
+}
+
+func (f *fakeOAuth) SignInWithOAuth(_ context.Context, body OAuthSignInRequest) (string, error) {
+	return "https://provider.example.com/authorize?redirect_to=" + url.QueryEscape(body.RedirectTo) +
+		"&code_challenge=" + body.CodeChallenge, nil
+}
+
+func (f *fakeOAuth) ExchangeCodeForSession(_ context.Context, body ExchangeCodeRequest) (*AuthDetailResp, error) {
+	f.exchanges = append(f.exchanges, body)
+	verifier, ok := f.codes[body.AuthCode]
+	if !ok {
+		return nil, &AuthError{Code: "flow_state_not_found", HTTPStatusCode: http.StatusNotFound}
+	}
+	if verifier != body.CodeVerifier {
+		return nil, &AuthError{Code: ErrBadCodeVerifier.Code, HTTPStatusCode: http.StatusBadRequest}
+	}
+	delete(f.codes, body.AuthCode)
+	return &AuthDetailResp{}, nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func newTestOAuthHandler(auth authAPI, sessions *int) *OAuthHandler {
+	return NewOAuthHandler(auth, OAuthHandlerConfig{
+		CallbackURL: testCallbackURL,
+		SuccessURL:  "https://example.com/dashboard",
+		FailureURL:  "https://example.com/login",
+		OnSession: func(w http.ResponseWriter, r *http.Request, session *AuthDetailResp) error {
+			*sessions++
+			return nil
+		},
+	})
+}
+
+// signIn starts a flow and returns its cookie and the verifier the provider was challenged with.
+func signIn(t *testing.T, handler *OAuthHandler) (*http.Cookie, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.SignIn(w, httptest.NewRequest(http.MethodGet, "/auth/github", nil), OAuthSignInRequest{Provider: "github"})
+	if w.Code != http.StatusFound {
+		t.Fatalf("SignIn() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := location.Query().Get("redirect_to"); got != testCallbackURL {
+		t.Errorf("SignIn() redirect_to = %q, want %q", got, testCallbackURL)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Name, oauthFlowCookiePrefix) {
+		t.Fatalf("SignIn() cookies = %v, want one flow cookie", cookies)
+	}
+	cookie := cookies[0]
+	if !cookie.HttpOnly || !cookie.Secure {
+		t.Errorf("SignIn() cookie is not http only and secure")
+	}
+	_, verifier, _ := strings.Cut(cookie.Value, ".")
+	if challenge := location.Query().Get("code_challenge"); challenge != pkceChallenge(verifier) {
+		t.Errorf("SignIn() code_challenge = %q, does not match the verifier of the cookie", challenge)
+	}
+	return cookie, verifier
+}
+
+func callback(handler *OAuthHandler, query string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/auth/callback?"+query, nil)
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func assertFailure(t *testing.T, w *httptest.ResponseRecorder, errCode string) {
+	t.Helper()
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusFound || location.Path != "/login" {
+		t.Fatalf("ServeHTTP() redirected to %s with %d, want the failure url", location, w.Code)
+	}
+	if got := location.Query().Get("error"); got != errCode {
+		t.Errorf("ServeHTTP() error = %q, want %q", got, errCode)
+	}
+}
+
+func TestOAuthHandlerExchange(t *testing.T) {
+	auth := &fakeOAuth{codes: map[string]string{}}
+	var sessions int
+	handler := newTestOAuthHandler(auth, &sessions)
+	cookie, verifier := signIn(t, handler)
+	auth.codes["code-1"] = verifier
+
+	w := callback(handler, "code=code-1", cookie)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "https://example.com/dashboard" {
+		t.Fatalf("ServeHTTP() redirected to %s with %d, want the success url", w.Header().Get("Location"), w.Code)
+	}
+	if sessions != 1 {
+		t.Errorf("OnSession called %d times, want 1", sessions)
+	}
+	cleared := w.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].Name != cookie.Name || cleared[0].MaxAge >= 0 {
+		t.Errorf("ServeHTTP() cookies = %v, want %s cleared", cleared, cookie.Name)
+	}
+}
+
+func TestOAuthHandlerParallelFlows(t *testing.T) {
+	auth := &fakeOAuth{codes: map[string]string{}}
+	var sessions int
+	handler := newTestOAuthHandler(auth, &sessions)
+	first, firstVerifier := signIn(t, handler)
+	second, _ := signIn(t, handler)
+	if first.Name == second.Name {
+		t.Fatalf("parallel flows share the cookie %s", first.Name)
+	}
+	auth.codes["code-1"] = firstVerifier
+
+	w := callback(handler, "code=code-1", first, second)
+	if sessions != 1 {
+		t.Fatalf("OnSession called %d times, want 1; exchanges %v", sessions, auth.exchanges)
+	}
+	cleared := w.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].Name != first.Name {
+		t.Errorf("ServeHTTP() cookies = %v, want only %s cleared", cleared, first.Name)
+	}
+}
+
+func TestOAuthHandlerFailure(t *testing.T) {
+	auth := &fakeOAuth{codes: map[string]string{}}
+	var sessions int
+	handler := newTestOAuthHandler(auth, &sessions)
+	cookie, verifier := signIn(t, handler)
+	auth.codes["code-1"] = verifier
+	auth.codes["foreign"] = "verifier-of-another-browser"
+
+	tests := []struct {
+		name    string
+		query   string
+		cookies []*http.Cookie
+		errCode string
+	}{
+		{name: "provider error", query: "error=access_denied&error_description=denied", cookies: []*http.Cookie{cookie}, errCode: "access_denied"},
+		{name: "missing code", query: "", cookies: []*http.Cookie{cookie}, errCode: "invalid_request"},
+		{name: "missing verifier", query: "code=code-1", errCode: "invalid_request"},
+		{name: "malformed verifier", query: "code=code-1", cookies: []*http.Cookie{{Name: cookie.Name, Value: "verifier"}}, errCode: "invalid_request"},
+		{name: "mismatched verifier", query: "code=foreign", cookies: []*http.Cookie{cookie}, errCode: "invalid_grant"},
+		{name: "unknown code", query: "code=unknown", cookies: []*http.Cookie{cookie}, errCode: "invalid_grant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFailure(t, callback(handler, tt.query, tt.cookies...), tt.errCode)
+		})
+	}
+	if sessions != 0 {
+		t.Errorf("OnSession called %d times, want 0", sessions)
+	}
+}
@@ -56,6 +56,11 @@ func New(cfg Config) (*Client, error) {
 	}, nil
 }
 
+// OnAuthStateChange subscribes to the auth events emitted by Auth, see Auth.OnAuthStateChange.
+func (c *Client) OnAuthStateChange(callback AuthStateChangeFunc) *AuthSubscription {
+	return c.Auth.OnAuthStateChange(callback)
+}
+
 func defaultSender(timeout time.Duration, header map[string]string) Sender {
 	httpClient := &http.Client{
 		Transport: &http.Transport{
@@ -1,6 +1,10 @@
 package supabase
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type AuthDetailResp struct {
 	AccessToken          string `json:"access_token,omitempty" url:"-"`
@@ -63,6 +67,9 @@ type OAuthSignInRequest struct {
 	Scopes           string `json:"-" url:"scopes,omitempty"`
 	Provider         string `json:"-" url:"provider,omitempty"`
 	SkipHTTPRedirect string `json:"-" url:"skip_http_redirect,omitempty"`
+	// CodeChallenge and CodeChallengeMethod from NewPKCE switch the sign in to the PKCE flow.
+	CodeChallenge       string `json:"-" url:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"-" url:"code_challenge_method,omitempty"`
 }
 
 type SignInWithIDTokenRequest struct {
@@ -108,24 +115,374 @@ type UrlOptions struct {
 	Download  bool              `json:"download"`
 }
 
+// AppMetadata holds the app_metadata of a user, which can only be changed with the service_role key.
+// Provider and Providers are set by GoTrue, the other keys are read with DecodeAppMetadata.
 type AppMetadata struct {
+	// Provider is the provider the user first signed up with.
 	Provider string `json:"provider"`
+	// Providers are all the providers linked to the user.
+	Providers []string `json:"providers"`
+	raw       json.RawMessage
 }
 
+func (m *AppMetadata) UnmarshalJSON(data []byte) error {
+	type fields AppMetadata
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*m = AppMetadata(f)
+	m.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON keeps every key of the decoded app_metadata, so a stored user is read back the same.
+func (m AppMetadata) MarshalJSON() ([]byte, error) {
+	if m.raw != nil {
+		return m.raw, nil
+	}
+	type fields AppMetadata
+	return json.Marshal(fields(m))
+}
+
+// UserMeta holds the user_metadata of a user, which can be changed by the user.
+// The keys other than Name are read with DecodeUserMetadata.
 type UserMeta struct {
 	Name string `json:"name"`
+	raw  json.RawMessage
 }
 
+func (m *UserMeta) UnmarshalJSON(data []byte) error {
+	type fields UserMeta
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*m = UserMeta(f)
+	m.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON keeps every key of the decoded user_metadata, so a stored user is read back the same.
+func (m UserMeta) MarshalJSON() ([]byte, error) {
+	if m.raw != nil {
+		return m.raw, nil
+	}
+	type fields UserMeta
+	return json.Marshal(fields(m))
+}
+
+// User refer from https://github.com/supabase/auth/blob/master/internal/models/user.go
+// Timestamps which may be absent are pointers, so a nil value means the event never happened.
 type User struct {
-	ID                 string      `json:"id"`
-	Aud                string      `json:"aud"`
-	Role               string      `json:"role"`
-	Email              string      `json:"email"`
-	InvitedAt          time.Time   `json:"invited_at"`
-	ConfirmedAt        time.Time   `json:"confirmed_at"`
-	ConfirmationSentAt time.Time   `json:"confirmation_sent_at"`
-	AppMetadata        AppMetadata `json:"app_metadata"`
-	Metadata           UserMeta    `json:"user_metadata"`
-	CreatedAt          time.Time   `json:"created_at"`
-	UpdatedAt          time.Time   `json:"updated_at"`
+	ID                     string      `json:"id"`
+	Aud                    string      `json:"aud"`
+	Role                   string      `json:"role"`
+	Email                  string      `json:"email"`
+	EmailConfirmedAt       *time.Time  `json:"email_confirmed_at,omitempty"`
+	Phone                  string      `json:"phone"`
+	PhoneConfirmedAt       *time.Time  `json:"phone_confirmed_at,omitempty"`
+	ConfirmationSentAt     *time.Time  `json:"confirmation_sent_at,omitempty"`
+	ConfirmedAt            *time.Time  `json:"confirmed_at,omitempty"`
+	RecoverySentAt         *time.Time  `json:"recovery_sent_at,omitempty"`
+	NewEmail               string      `json:"new_email,omitempty"`
+	EmailChangeSentAt      *time.Time  `json:"email_change_sent_at,omitempty"`
+	NewPhone               string      `json:"new_phone,omitempty"`
+	PhoneChangeSentAt      *time.Time  `json:"phone_change_sent_at,omitempty"`
+	ReauthenticationSentAt *time.Time  `json:"reauthentication_sent_at,omitempty"`
+	InvitedAt              *time.Time  `json:"invited_at,omitempty"`
+	LastSignInAt           *time.Time  `json:"last_sign_in_at,omitempty"`
+	AppMetadata            AppMetadata `json:"app_metadata"`
+	Metadata               UserMeta    `json:"user_metadata"`
+	Identities             []Identity  `json:"identities"`
+	Factors                []Factor    `json:"factors,omitempty"`
+	IsAnonymous            bool        `json:"is_anonymous"`
+	IsSSOUser              bool        `json:"is_sso_user"`
+	BannedUntil            *time.Time  `json:"banned_until,omitempty"`
+	CreatedAt              time.Time   `json:"created_at"`
+	UpdatedAt              time.Time   `json:"updated_at"`
+	DeletedAt              *time.Time  `json:"deleted_at,omitempty"`
+}
+
+// DecodeUserMetadata decodes the user_metadata of a user into T.
+func DecodeUserMetadata[T any](user *User) (T, error) {
+	raw, err := user.Metadata.MarshalJSON()
+	if err != nil {
+		var out T
+		return out, err
+	}
+	return decodeMetadata[T](raw)
+}
+
+// DecodeAppMetadata decodes the app_metadata of a user into T.
+func DecodeAppMetadata[T any](user *User) (T, error) {
+	raw, err := user.AppMetadata.MarshalJSON()
+	if err != nil {
+		var out T
+		return out, err
+	}
+	return decodeMetadata[T](raw)
+}
+
+// decodeMetadata decodes the raw json into T directly, so numbers keep their precision, e.g. int64 ids.
+func decodeMetadata[T any](raw json.RawMessage) (T, error) {
+	var out T
+	if string(raw) == "null" {
+		return out, nil
+	}
+	err := json.Unmarshal(raw, &out)
+	return out, err
+}
+
+type ListUsersRequest struct {
+	Page    int `json:"-" url:"page,omitempty"`
+	PerPage int `json:"-" url:"per_page,omitempty"`
+}
+
+type ListUsersResp struct {
+	Users    []User `json:"users"`
+	Aud      string `json:"aud"`
+	NextPage int    `json:"-"`
+	LastPage int    `json:"-"`
+	Total    int    `json:"-"`
+}
+
+type AdminUserAttributes struct {
+	Email        string      `json:"email,omitempty" url:"-"`
+	Phone        string      `json:"phone,omitempty" url:"-"`
+	Password     string      `json:"password,omitempty" url:"-"`
+	EmailConfirm bool        `json:"email_confirm,omitempty" url:"-"`
+	PhoneConfirm bool        `json:"phone_confirm,omitempty" url:"-"`
+	Role         string      `json:"role,omitempty" url:"-"`
+	UserMetadata interface{} `json:"user_metadata,omitempty" url:"-"`
+	AppMetadata  interface{} `json:"app_metadata,omitempty" url:"-"`
+	// BanDuration is a duration string such as "24h", or "none" to lift an existing ban.
+	BanDuration string `json:"ban_duration,omitempty" url:"-"`
+}
+
+type DeleteUserRequest struct {
+	ShouldSoftDelete bool `json:"should_soft_delete" url:"-"`
+}
+
+type InviteUserByEmailRequest struct {
+	Email      string      `json:"email" url:"-"`
+	Data       interface{} `json:"data,omitempty" url:"-"`
+	RedirectTo string      `json:"-" url:"redirect_to,omitempty"`
+}
+
+type GenerateLinkRequest struct {
+	Type       LinkType    `json:"type" url:"-"`
+	Email      string      `json:"email" url:"-"`
+	NewEmail   string      `json:"new_email,omitempty" url:"-"`
+	Password   string      `json:"password,omitempty" url:"-"`
+	Data       interface{} `json:"data,omitempty" url:"-"`
+	RedirectTo string      `json:"redirect_to,omitempty" url:"-"`
+}
+
+type GenerateLinkResp struct {
+	User
+	ActionLink       string `json:"action_link"`
+	EmailOTP         string `json:"email_otp"`
+	HashedToken      string `json:"hashed_token"`
+	RedirectTo       string `json:"redirect_to"`
+	VerificationType string `json:"verification_type"`
+}
+
+type Factor struct {
+	ID           string    `json:"id"`
+	FriendlyName string    `json:"friendly_name"`
+	FactorType   string    `json:"factor_type"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type MFAEnrollRequest struct {
+	FactorType   string `json:"factor_type" url:"-"`
+	FriendlyName string `json:"friendly_name,omitempty" url:"-"`
+	Issuer       string `json:"issuer,omitempty" url:"-"`
+	Phone        string `json:"phone,omitempty" url:"-"`
+}
+
+type TOTPDetail struct {
+	QRCode string `json:"qr_code"`
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type MFAEnrollResp struct {
+	ID           string      `json:"id"`
+	Type         string      `json:"type"`
+	FriendlyName string      `json:"friendly_name"`
+	TOTP         *TOTPDetail `json:"totp,omitempty"`
+	Phone        string      `json:"phone,omitempty"`
+}
+
+type MFAChallengeRequest struct {
+	FactorID string `json:"-" url:"-"`
+	// Channel is either "sms" or "whatsapp" and is only used by phone factors.
+	Channel string `json:"channel,omitempty" url:"-"`
+}
+
+type MFAChallengeResp struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type MFAVerifyRequest struct {
+	FactorID    string `json:"-" url:"-"`
+	ChallengeID string `json:"challenge_id" url:"-"`
+	Code        string `json:"code" url:"-"`
+}
+
+type MFAChallengeAndVerifyRequest struct {
+	FactorID string `json:"-" url:"-"`
+	Code     string `json:"code" url:"-"`
+}
+
+type MFAUnenrollResp struct {
+	ID string `json:"id"`
+}
+
+type MFAListFactorsResp struct {
+	All   []Factor
+	TOTP  []Factor
+	Phone []Factor
+}
+
+type AMREntry struct {
+	Method    string `json:"method"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type AuthenticatorAssuranceLevelResp struct {
+	CurrentLevel                 string
+	NextLevel                    string
+	CurrentAuthenticationMethods []AMREntry
+}
+
+type Identity struct {
+	ID           string                 `json:"id"`
+	IdentityID   string                 `json:"identity_id"`
+	UserID       string                 `json:"user_id"`
+	IdentityData map[string]interface{} `json:"identity_data"`
+	Provider     string                 `json:"provider"`
+	Email        string                 `json:"email"`
+	LastSignInAt *time.Time             `json:"last_sign_in_at,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+type LinkIdentityRequest struct {
+	Provider         string `json:"-" url:"provider"`
+	RedirectTo       string `json:"-" url:"redirect_to,omitempty"`
+	Scopes           string `json:"-" url:"scopes,omitempty"`
+	SkipHTTPRedirect bool   `json:"-" url:"skip_http_redirect"`
+}
+
+type LinkIdentityResp struct {
+	URL string `json:"url"`
+}
+
+type ResendRequest struct {
+	// Type takes one of VerifyTypeSignUp, VerifyTypeEmailChange, VerifyTypeSms or VerifyTypePhoneChange.
+	Type               VerifyType `json:"type" url:"-"`
+	Email              string     `json:"email,omitempty" url:"-"`
+	Phone              string     `json:"phone,omitempty" url:"-"`
+	GotrueMetaSecurity GotrueMeta `json:"gotrue_meta_security,omitempty" url:"-"`
+	EmailRedirectTo    string     `json:"-" url:"redirect_to,omitempty"`
+}
+
+type ResendResp struct {
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type ExchangeCodeRequest struct {
+	AuthCode     string `json:"auth_code" url:"-"`
+	CodeVerifier string `json:"code_verifier" url:"-"`
+}
+
+type SignInWithSSORequest struct {
+	// Domain or ProviderID identifies the identity provider. Exactly one of them is set.
+	Domain              string     `json:"domain,omitempty" url:"-"`
+	ProviderID          string     `json:"provider_id,omitempty" url:"-"`
+	RedirectTo          string     `json:"redirect_to,omitempty" url:"-"`
+	CodeChallengeMethod string     `json:"code_challenge_method,omitempty" url:"-"`
+	CodeChallenge       string     `json:"code_challenge,omitempty" url:"-"`
+	GotrueMetaSecurity  GotrueMeta `json:"gotrue_meta_security,omitempty" url:"-"`
+	SkipHTTPRedirect    bool       `json:"skip_http_redirect" url:"-"`
+}
+
+type SignInWithSSOResp struct {
+	URL string `json:"url"`
+}
+
+type SAMLAttributeMapping struct {
+	Keys map[string]SAMLAttribute `json:"keys"`
+}
+
+type SAMLAttribute struct {
+	Name    string      `json:"name,omitempty"`
+	Names   []string    `json:"names,omitempty"`
+	Default interface{} `json:"default,omitempty"`
+	Array   bool        `json:"array,omitempty"`
+}
+
+type SAMLProvider struct {
+	EntityID         string                `json:"entity_id"`
+	MetadataURL      string                `json:"metadata_url,omitempty"`
+	MetadataXML      string                `json:"metadata_xml,omitempty"`
+	AttributeMapping *SAMLAttributeMapping `json:"attribute_mapping,omitempty"`
+}
+
+type SSODomain struct {
+	ID        string    `json:"id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SSOProvider struct {
+	ID        string        `json:"id"`
+	SAML      *SAMLProvider `json:"saml,omitempty"`
+	Domains   []SSODomain   `json:"domains"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type ListSSOProvidersResp struct {
+	Items []SSOProvider `json:"items"`
+}
+
+type SSOProviderRequest struct {
+	// Type is always "saml" and is only needed when creating a provider.
+	Type             string                `json:"type,omitempty" url:"-"`
+	MetadataURL      string                `json:"metadata_url,omitempty" url:"-"`
+	MetadataXML      string                `json:"metadata_xml,omitempty" url:"-"`
+	Domains          []string              `json:"domains,omitempty" url:"-"`
+	AttributeMapping *SAMLAttributeMapping `json:"attribute_mapping,omitempty" url:"-"`
+}
+
+type AuthSettings struct {
+	External          map[string]bool `json:"external"`
+	DisableSignup     bool            `json:"disable_signup"`
+	MailerAutoconfirm bool            `json:"mailer_autoconfirm"`
+	PhoneAutoconfirm  bool            `json:"phone_autoconfirm"`
+	SMSProvider       string          `json:"sms_provider"`
+	SAMLEnabled       bool            `json:"saml_enabled"`
+}
+
+// IsProviderEnabled reports whether the oauth provider is enabled on the project.
+func (s AuthSettings) IsProviderEnabled(provider Provider) bool {
+	return s.External[provider.String()]
+}
+
+// ValidateProvider returns ErrProviderDisabled when the oauth provider is not enabled on the project.
+// Call it before building the url with SignInWithOAuth.
+func (s AuthSettings) ValidateProvider(provider Provider) error {
+	if !s.IsProviderEnabled(provider) {
+		return fmt.Errorf("%w: %s", ErrProviderDisabled, provider)
+	}
+	return nil
 }
@@ -0,0 +1,87 @@
+package supabase
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testUserJSON = `{
+	"id": "user-1",
+	"app_metadata": {"provider": "email", "providers": ["email", "github"], "tenant_id": 9007199254740993},
+	"user_metadata": {"name": "Alice", "avatar": "a.png", "score": 9007199254740993}
+}`
+
+func TestUserMetadata(t *testing.T) {
+	var user User
+	if err := json.Unmarshal([]byte(testUserJSON), &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.AppMetadata.Provider != "email" || len(user.AppMetadata.Providers) != 2 || user.AppMetadata.Providers[1] != "github" {
+		t.Errorf("app metadata = %+v", user.AppMetadata)
+	}
+	if user.Metadata.Name != "Alice" {
+		t.Errorf("user metadata name = %s", user.Metadata.Name)
+	}
+
+	app, err := DecodeAppMetadata[struct {
+		TenantID int64 `json:"tenant_id"`
+	}](&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.TenantID != 9007199254740993 {
+		t.Errorf("tenant id = %d, precision is lost", app.TenantID)
+	}
+	profile, err := DecodeUserMetadata[struct {
+		Avatar string `json:"avatar"`
+		Score  int64  `json:"score"`
+	}](&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Avatar != "a.png" || profile.Score != 9007199254740993 {
+		t.Errorf("profile = %+v", profile)
+	}
+}
+
+func TestUserMetadataRoundTrip(t *testing.T) {
+	var user User
+	if err := json.Unmarshal([]byte(testUserJSON), &user); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded User
+	if err = json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := DecodeUserMetadata[map[string]interface{}](&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile["avatar"] != "a.png" {
+		t.Errorf("user metadata lost keys in a round trip: %v", profile)
+	}
+
+	// A user built in code has no raw json, so its fields are encoded.
+	built, err := json.Marshal(UserMeta{Name: "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(built) != `{"name":"Bob"}` {
+		t.Errorf("built user metadata = %s", built)
+	}
+}
+
+func TestDecodeMetadataEmpty(t *testing.T) {
+	var user User
+	if err := json.Unmarshal([]byte(`{"id":"user-1","user_metadata":null}`), &user); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := DecodeUserMetadata[map[string]interface{}](&user)
+	if err != nil || profile != nil {
+		t.Errorf("profile = %v, err = %v", profile, err)
+	}
+}
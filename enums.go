@@ -29,6 +29,11 @@ func (v VerifyType) String() string {
 	return [...]string{"signup", "invite", "magiclink", "recovery", "email_change", "email", "sms", "phone_change"}[v]
 }
 
+// MarshalText encodes the verify type as its string value in json.
+func (v VerifyType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
 type Order uint8
 
 const (
@@ -91,3 +96,95 @@ func (v Provider) String() string {
 		"fly",
 	}[v]
 }
+
+type LinkType uint8
+
+const (
+	LinkTypeSignUp LinkType = iota
+	LinkTypeInvite
+	LinkTypeMagicLink
+	LinkTypeRecovery
+	LinkTypeEmailChangeCurrent
+	LinkTypeEmailChangeNew
+)
+
+func (v LinkType) String() string {
+	return [...]string{"signup", "invite", "magiclink", "recovery", "email_change_current", "email_change_new"}[v]
+}
+
+// MarshalText encodes the link type as its string value in json.
+func (v LinkType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+type FactorType uint8
+
+const (
+	FactorTypeTOTP FactorType = iota
+	FactorTypePhone
+)
+
+func (v FactorType) String() string {
+	return [...]string{"totp", "phone"}[v]
+}
+
+type AuthenticatorAssuranceLevel uint8
+
+const (
+	AAL1 AuthenticatorAssuranceLevel = iota
+	AAL2
+)
+
+func (v AuthenticatorAssuranceLevel) String() string {
+	return [...]string{"aal1", "aal2"}[v]
+}
+
+type SignOutScope uint8
+
+const (
+	SignOutScopeGlobal SignOutScope = iota
+	SignOutScopeLocal
+	SignOutScopeOthers
+)
+
+func (v SignOutScope) String() string {
+	return [...]string{"global", "local", "others"}[v]
+}
+
+type AuthChangeEvent uint8
+
+const (
+	AuthChangeEventSignedIn AuthChangeEvent = iota
+	AuthChangeEventSignedOut
+	AuthChangeEventTokenRefreshed
+	AuthChangeEventUserUpdated
+	AuthChangeEventPasswordRecovery
+	AuthChangeEventMFAChallengeVerified
+)
+
+func (v AuthChangeEvent) String() string {
+	return [...]string{"SIGNED_IN", "SIGNED_OUT", "TOKEN_REFRESHED", "USER_UPDATED", "PASSWORD_RECOVERY", "MFA_CHALLENGE_VERIFIED"}[v]
+}
+
+type HookDecision uint8
+
+const (
+	HookDecisionContinue HookDecision = iota
+	HookDecisionReject
+)
+
+func (v HookDecision) String() string {
+	return [...]string{"continue", "reject"}[v]
+}
+
+type Count uint8
+
+const (
+	CountExact Count = iota
+	CountPlanned
+	CountEstimated
+)
+
+func (v Count) String() string {
+	return [...]string{"exact", "planned", "estimated"}[v]
+}
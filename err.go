@@ -1,13 +1,25 @@
 package supabase
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
 
 type Exception interface {
 	Error() string
 	StatusCode() int
 }
 
-var ErrEmptyApiKey = errors.New("apiKey is mandatory")
+var (
+	ErrEmptyApiKey           = errors.New("apiKey is mandatory")
+	ErrUnsupportedResendType = errors.New("resend type must be signup, email_change, sms or phone_change")
+	ErrSSODomainOrProviderID = errors.New("either domain or provider id is mandatory for sso")
+	ErrNoSessionCookie       = errors.New("session cookie is not found")
+	ErrProviderDisabled      = errors.New("provider is not enabled")
+	ErrKeysetColumn          = errors.New("keyset column must be selected and not null")
+	ErrTableName             = errors.New("table name is mandatory for a row type without TableName method")
+)
 
 type externalErr struct {
 	body       []byte
@@ -38,5 +50,19 @@ type PostgresError struct {
 }
 
 func (rq *PostgresError) Error() string {
+	if rq.Code == "" {
+		return rq.Message
+	}
 	return rq.Code + ": " + rq.Message
 }
+
+// postgresError parses the response body of a failed PostgREST request into a PostgresError. The body is
+// empty for a HEAD request and may not be json from a proxy, then the status text is the message.
+func postgresError(body []byte, statusCode int) *PostgresError {
+	var reqError PostgresError
+	if err := json.Unmarshal(body, &reqError); err != nil || reqError.Message == "" {
+		reqError = PostgresError{Message: http.StatusText(statusCode)}
+	}
+	reqError.HTTPStatusCode = statusCode
+	return &reqError
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 )
 
 const (
@@ -58,8 +60,21 @@ func printHeader(header http.Header) []byte {
 	return nil
 }
 
+// queryValues reads the `url` tags of a struct body into the query string. Other bodies, such as the
+// maps and slices of rows written to PostgREST, have no query string.
+func queryValues(body any) (url.Values, error) {
+	rv := reflect.ValueOf(body)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	return Values(body)
+}
+
 func (c requester) Call(ctx context.Context, fullUrl, method string, body any, customHeaders HeaderSetter) (*Resp, error) {
-	qs, err := Values(body)
+	qs, err := queryValues(body)
 	if err != nil {
 		logger.Error("failed in retrieving query string with err: %s", err)
 		return nil, err
@@ -77,7 +92,7 @@ func (c requester) Call(ctx context.Context, fullUrl, method string, body any, c
 		logger.Error("failed in new request with context with err: %s", err)
 		return nil, err
 	}
-	httpReq.Header = c.customHeader
+	httpReq.Header = c.customHeader.Clone()
 	httpReq.Header.Set(headerContentType, applicationJSON)
 	httpReq.Header.Set(headerAccept, applicationJSON)
 	customHeaders(httpReq)
@@ -111,7 +126,7 @@ func (c requester) Upload(ctx context.Context, fullUrl, method string, file io.R
 		logger.Error("failed in new request with context with err: %s", err)
 		return nil, err
 	}
-	httpReq.Header = c.customHeader
+	httpReq.Header = c.customHeader.Clone()
 	customHeaders(httpReq)
 
 	var httpResp *http.Response
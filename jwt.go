@@ -0,0 +1,116 @@
+package supabase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTokenRole     = "authenticated"
+	defaultTokenAudience = "authenticated"
+	defaultTokenExpiry   = time.Hour
+)
+
+var (
+	ErrInvalidJWT     = errors.New("invalid jwt")
+	ErrEmptyJWTSecret = errors.New("jwt secret is mandatory")
+)
+
+// AccessTokenClaims are the claims of a minted access token. Role and Audience default to "authenticated"
+// and ExpiresAt defaults to one hour from now. Extra claims are added as is, but never override the other fields.
+type AccessTokenClaims struct {
+	Subject      string
+	Role         string
+	Audience     string
+	Email        string
+	Phone        string
+	Issuer       string
+	SessionID    string
+	IsAnonymous  bool
+	ExpiresAt    time.Time
+	AppMetadata  map[string]interface{}
+	UserMetadata map[string]interface{}
+	Extra        map[string]interface{}
+}
+
+// MintAccessToken signs an HS256 access token with the jwt secret of the project, so PostgREST and row level
+// security see the request as the given user without a real sign in. Use it with AuthToken on From and RPC.
+// Auth.User also accepts the token as long as Subject is the id of an existing user.
+// The jwt secret grants any role, so it must only be used on a trusted server or in tests.
+func MintAccessToken(jwtSecret string, claims AccessTokenClaims) (string, error) {
+	if jwtSecret == "" {
+		return "", ErrEmptyJWTSecret
+	}
+	now := time.Now()
+	payload := make(map[string]interface{}, len(claims.Extra)+12)
+	for k, v := range claims.Extra {
+		payload[k] = v
+	}
+	payload["sub"] = claims.Subject
+	payload["role"] = defaultTokenRole
+	if claims.Role != "" {
+		payload["role"] = claims.Role
+	}
+	payload["aud"] = defaultTokenAudience
+	if claims.Audience != "" {
+		payload["aud"] = claims.Audience
+	}
+	expiresAt := now.Add(defaultTokenExpiry)
+	if !claims.ExpiresAt.IsZero() {
+		expiresAt = claims.ExpiresAt
+	}
+	payload["exp"] = expiresAt.Unix()
+	payload["iat"] = now.Unix()
+	payload["aal"] = AAL1.String()
+	payload["is_anonymous"] = claims.IsAnonymous
+	if claims.Email != "" {
+		payload["email"] = claims.Email
+	}
+	if claims.Phone != "" {
+		payload["phone"] = claims.Phone
+	}
+	if claims.Issuer != "" {
+		payload["iss"] = claims.Issuer
+	}
+	if claims.SessionID != "" {
+		payload["session_id"] = claims.SessionID
+	}
+	if claims.AppMetadata != nil {
+		payload["app_metadata"] = claims.AppMetadata
+	}
+	if claims.UserMetadata != nil {
+		payload["user_metadata"] = claims.UserMetadata
+	}
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error("failed in marshal jwt claims with err: %s", err)
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// decodeJWTClaims decodes the payload of a jwt into claims. The signature is not verified,
+// so the claims must only be trusted when the token comes from a trusted source.
+func decodeJWTClaims(token string, claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidJWT
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return ErrInvalidJWT
+	}
+	return json.Unmarshal(payload, claims)
+}
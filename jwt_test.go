@@ -0,0 +1,122 @@
+package supabase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJWTSecret = "super-secret-jwt-token-with-at-least-32-characters"
+
+// verifyHS256 checks the signature of token with secret and returns its header and claims.
+func verifyHS256(t *testing.T, token, secret string) (map[string]interface{}, map[string]interface{}) {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts, want 3", len(parts))
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		t.Fatal("token signature does not verify with the secret")
+	}
+	decode := func(part string) map[string]interface{} {
+		raw, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded map[string]interface{}
+		if err = json.Unmarshal(raw, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		return decoded
+	}
+	return decode(parts[0]), decode(parts[1])
+}
+
+func TestMintAccessToken(t *testing.T) {
+	expiresAt := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	token, err := MintAccessToken(testJWTSecret, AccessTokenClaims{
+		Subject:      "user-1",
+		Role:         "service_role",
+		Audience:     "internal",
+		Email:        "alice@example.com",
+		ExpiresAt:    expiresAt,
+		UserMetadata: map[string]interface{}{"name": "Alice"},
+		Extra:        map[string]interface{}{"tenant_id": "t-1", "sub": "overridden"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, claims := verifyHS256(t, token, testJWTSecret)
+	if header["alg"] != "HS256" || header["typ"] != "JWT" {
+		t.Errorf("header = %v", header)
+	}
+	want := map[string]interface{}{
+		"sub":       "user-1",
+		"role":      "service_role",
+		"aud":       "internal",
+		"email":     "alice@example.com",
+		"exp":       float64(expiresAt.Unix()),
+		"tenant_id": "t-1",
+	}
+	for k, v := range want {
+		if claims[k] != v {
+			t.Errorf("claim %s = %v, want %v", k, claims[k], v)
+		}
+	}
+	if metadata, _ := claims["user_metadata"].(map[string]interface{}); metadata["name"] != "Alice" {
+		t.Errorf("claim user_metadata = %v", claims["user_metadata"])
+	}
+
+	var decoded struct {
+		Subject string `json:"sub"`
+	}
+	if err = decodeJWTClaims(token, &decoded); err != nil || decoded.Subject != "user-1" {
+		t.Errorf("decodeJWTClaims() = %+v, %v", decoded, err)
+	}
+}
+
+func TestMintAccessTokenDefaults(t *testing.T) {
+	before := time.Now()
+	token, err := MintAccessToken(testJWTSecret, AccessTokenClaims{Subject: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, claims := verifyHS256(t, token, testJWTSecret)
+	if claims["role"] != "authenticated" || claims["aud"] != "authenticated" {
+		t.Errorf("role = %v, aud = %v, want authenticated", claims["role"], claims["aud"])
+	}
+	exp, _ := claims["exp"].(float64)
+	if want := before.Add(time.Hour).Unix(); int64(exp) < want || int64(exp) > want+1 {
+		t.Errorf("exp = %v, want one hour from now", exp)
+	}
+	if _, ok := claims["email"]; ok {
+		t.Errorf("email claim is set without Email")
+	}
+}
+
+func TestMintAccessTokenSecret(t *testing.T) {
+	if _, err := MintAccessToken("", AccessTokenClaims{Subject: "user-1"}); !errors.Is(err, ErrEmptyJWTSecret) {
+		t.Errorf("MintAccessToken() with empty secret err = %v, want %v", err, ErrEmptyJWTSecret)
+	}
+	token, err := MintAccessToken(testJWTSecret, AccessTokenClaims{Subject: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	mac := hmac.New(sha256.New, []byte("another-secret"))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) == parts[2] {
+		t.Error("token verifies with another secret")
+	}
+}
@@ -38,6 +38,12 @@ var (
 	logger *zeroLogger
 )
 
+// The logger is disabled until New configures it, so the standalone entry points such as HookHandler
+// and NewCookieSession can log without a client.
+func init() {
+	newLogger(false)
+}
+
 type zeroLogger struct {
 	zeroLogger zerolog.Logger
 }
@@ -0,0 +1,33 @@
+package supabase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const codeChallengeMethodS256 = "s256"
+
+// PKCE holds a code verifier and its challenge. Send CodeChallenge with the sign in request, keep
+// CodeVerifier on the server and pass it to ExchangeCodeForSession together with the returned auth code.
+type PKCE struct {
+	CodeVerifier        string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// NewPKCE generates a random code verifier with its S256 challenge.
+func NewPKCE() (*PKCE, error) {
+	buf := make([]byte, 56)
+	if _, err := rand.Read(buf); err != nil {
+		logger.Error("failed in generate code verifier with err: %s", err)
+		return nil, err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(buf)
+	challenge := sha256.Sum256([]byte(verifier))
+	return &PKCE{
+		CodeVerifier:        verifier,
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(challenge[:]),
+		CodeChallengeMethod: codeChallengeMethodS256,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerPrefer       = "Prefer"
+	headerContentRange = "Content-Range"
+	preferReturnRep    = "return=representation"
+)
+
+var ErrInvalidContentRange = errors.New("invalid content range")
+
+// ContentRange is the parsed Content-Range header of PostgREST, e.g. `0-24/3573`.
+// From and To are -1 when no row is returned and Total is -1 when no count is requested.
+type ContentRange struct {
+	From  int64
+	To    int64
+	Total int64
+}
+
+// ParseContentRange parses the Content-Range header of PostgREST, which is `<from>-<to>/<total>`,
+// `*/<total>` when no row is returned and `<from>-<to>/*` when the total is not counted.
+func ParseContentRange(value string) (*ContentRange, error) {
+	rows, total, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return nil, ErrInvalidContentRange
+	}
+	contentRange := &ContentRange{From: -1, To: -1, Total: -1}
+	if rows != "*" {
+		from, to, found := strings.Cut(rows, "-")
+		if !found {
+			return nil, ErrInvalidContentRange
+		}
+		var err error
+		if contentRange.From, err = strconv.ParseInt(from, 10, 64); err != nil {
+			return nil, ErrInvalidContentRange
+		}
+		if contentRange.To, err = strconv.ParseInt(to, 10, 64); err != nil {
+			return nil, ErrInvalidContentRange
+		}
+	}
+	if total != "*" {
+		var err error
+		if contentRange.Total, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return nil, ErrInvalidContentRange
+		}
+	}
+	return contentRange, nil
+}
+
+// contentRange parses the Content-Range header of the response, which is nil when it is absent.
+func contentRange(header http.Header) (*ContentRange, error) {
+	value := header.Get(headerContentRange)
+	if value == "" {
+		return nil, nil
+	}
+	return ParseContentRange(value)
+}
+
+// addPreference appends a preference to the Prefer header, replacing the preference with the same name,
+// so `count=exact` and `return=representation` can be combined.
+func addPreference(header http.Header, preference string) {
+	name, _, _ := strings.Cut(preference, "=")
+	preferences := []string{preference}
+	for _, p := range splitPreferences(header.Get(headerPrefer)) {
+		if existing, _, _ := strings.Cut(p, "="); existing != name {
+			preferences = append(preferences, p)
+		}
+	}
+	header.Set(headerPrefer, strings.Join(preferences, ","))
+}
+
+// removePreference removes a preference from the Prefer header.
+func removePreference(header http.Header, preference string) {
+	var preferences []string
+	for _, p := range splitPreferences(header.Get(headerPrefer)) {
+		if p != preference {
+			preferences = append(preferences, p)
+		}
+	}
+	header.Set(headerPrefer, strings.Join(preferences, ","))
+}
+
+func splitPreferences(value string) []string {
+	var preferences []string
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			preferences = append(preferences, p)
+		}
+	}
+	return preferences
+}
@@ -0,0 +1,136 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  ContentRange
+		err   error
+	}{
+		{name: "rows and total", value: "0-24/3573", want: ContentRange{From: 0, To: 24, Total: 3573}},
+		{name: "no rows", value: "*/0", want: ContentRange{From: -1, To: -1, Total: 0}},
+		{name: "no total", value: "0-9/*", want: ContentRange{From: 0, To: 9, Total: -1}},
+		{name: "no rows and no total", value: "*/*", want: ContentRange{From: -1, To: -1, Total: -1}},
+		{name: "missing total", value: "0-9", err: ErrInvalidContentRange},
+		{name: "missing to", value: "0/10", err: ErrInvalidContentRange},
+		{name: "not a number", value: "a-b/c", err: ErrInvalidContentRange},
+		{name: "empty", value: "", err: ErrInvalidContentRange},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseContentRange(tc.value)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("ParseContentRange(%q) err = %v, want %v", tc.value, err, tc.err)
+			}
+			if err == nil && *got != tc.want {
+				t.Errorf("ParseContentRange(%q) = %+v, want %+v", tc.value, *got, tc.want)
+			}
+		})
+	}
+}
+
+// countHandler answers every request with the given status, Content-Range and body, and records the
+// method and Prefer header of the last request.
+func countHandler(status int, contentRange, body string, method, prefer *string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*method = r.Method
+		*prefer = r.Header.Get(headerPrefer)
+		if contentRange != "" {
+			w.Header().Set(headerContentRange, contentRange)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestSelectCount(t *testing.T) {
+	var method, prefer string
+	db := newTestPostgresHandler(t, countHandler(http.StatusOK, "0-1/42", `[{"id":1},{"id":2}]`, &method, &prefer))
+	var rows []map[string]interface{}
+	got, err := db.From("todos").Select("id").Count(CountExact).Range(0, 1).ExecuteWithCount(context.Background(), &rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodGet || prefer != "count=exact" {
+		t.Errorf("method = %s, prefer = %s", method, prefer)
+	}
+	if len(rows) != 2 || *got != (ContentRange{From: 0, To: 1, Total: 42}) {
+		t.Errorf("rows = %v, content range = %+v", rows, *got)
+	}
+}
+
+func TestSelectHeadCount(t *testing.T) {
+	var method, prefer string
+	db := newTestPostgresHandler(t, countHandler(http.StatusOK, "*/42", "", &method, &prefer))
+	var rows []map[string]interface{}
+	got, err := db.From("todos").Select("*").Count(CountPlanned).Head().ExecuteWithCount(context.Background(), &rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodHead || prefer != "count=planned" {
+		t.Errorf("method = %s, prefer = %s", method, prefer)
+	}
+	if rows != nil || got.Total != 42 {
+		t.Errorf("rows = %v, total = %d", rows, got.Total)
+	}
+}
+
+func TestRPCHeadCount(t *testing.T) {
+	var method, prefer string
+	db := newTestPostgresHandler(t, countHandler(http.StatusOK, "*/7", "", &method, &prefer))
+	got, err := db.RPC("list_todos", map[string]interface{}{"done": true}).Count(CountExact).Head().
+		ExecuteWithCount(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodHead || prefer != "count=exact" || got.Total != 7 {
+		t.Errorf("method = %s, prefer = %s, total = %d", method, prefer, got.Total)
+	}
+}
+
+func TestPostgresErrorStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		head    bool
+		code    string
+		message string
+	}{
+		{name: "json error", status: http.StatusBadRequest, body: `{"code":"42703","message":"column todos.x does not exist"}`, code: "42703", message: "column todos.x does not exist"},
+		{name: "head", status: http.StatusNotFound, head: true, message: "Not Found"},
+		{name: "not json", status: http.StatusBadGateway, body: "<html>bad gateway</html>", message: "Bad Gateway"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var method, prefer string
+			db := newTestPostgresHandler(t, countHandler(tc.status, "", tc.body, &method, &prefer))
+			query := db.From("todos").Select("*")
+			rpc := db.RPC("list_todos", nil)
+			if tc.head {
+				query.Head()
+				rpc.Head()
+			}
+			var rows []map[string]interface{}
+			for _, err := range []error{
+				query.Execute(context.Background(), &rows),
+				rpc.Execute(context.Background(), &rows),
+			} {
+				var pgErr *PostgresError
+				if !errors.As(err, &pgErr) {
+					t.Fatalf("err = %v, want a PostgresError", err)
+				}
+				if pgErr.HTTPStatusCode != tc.status || pgErr.Code != tc.code || pgErr.Message != tc.message {
+					t.Errorf("err = %+v, want status %d, code %q and message %q", pgErr, tc.status, tc.code, tc.message)
+				}
+			}
+		})
+	}
+}
@@ -1,10 +1,5 @@
 package supabase
 
-import (
-	"fmt"
-	"strings"
-)
-
 // FilterRequestBuilder represents a builder for filter requests.
 type FilterRequestBuilder struct {
 	QueryRequestBuilder
@@ -17,19 +12,50 @@ func (b *FilterRequestBuilder) Not() *FilterRequestBuilder {
 	return b
 }
 
-// Filter adds a filter condition to the request.
-func (b *FilterRequestBuilder) Filter(column, operator, criteria string) *FilterRequestBuilder {
+// Count requests the number of rows matched by the request, which is returned in the Content-Range
+// header and read with ExecuteWithCount.
+func (b *FilterRequestBuilder) Count(count Count) *FilterRequestBuilder {
+	addPreference(b.header, "count="+count.String())
+	return b
+}
+
+// Filter adds a filter condition to the request. The criteria is sent as is, so it must already be
+// escaped for the operator; the typed methods such as Eq and In escape their values. Like every column
+// filter, WithReferencedTable filters the rows of an embedded resource instead, e.g. `author.name=eq.x`.
+func (b *FilterRequestBuilder) Filter(column, operator, criteria string, opts ...QueryOption) *FilterRequestBuilder {
+	if b.negateNext {
+		b.negateNext = false
+		operator = "not." + operator
+	}
+	b.params.Add(newQueryOptions(opts).paramKey(column), operator+"."+criteria)
+	return b
+}
+
+// Or adds a group of conditions where any condition must match, e.g. `or=(status.eq.open,priority.gt.3)`.
+// Call Not before to negate the whole group. WithReferencedTable applies the group to an embedded resource.
+func (b *FilterRequestBuilder) Or(group *FilterGroup, opts ...QueryOption) *FilterRequestBuilder {
+	return b.logical("or", group, opts)
+}
+
+// And adds a group of conditions where every condition must match. Filters are already combined with AND,
+// so it is mostly useful with Not or WithReferencedTable.
+func (b *FilterRequestBuilder) And(group *FilterGroup, opts ...QueryOption) *FilterRequestBuilder {
+	return b.logical("and", group, opts)
+}
+
+func (b *FilterRequestBuilder) logical(operator string, group *FilterGroup, opts []QueryOption) *FilterRequestBuilder {
 	if b.negateNext {
 		b.negateNext = false
 		operator = "not." + operator
 	}
-	b.params.Add(column, operator+"."+criteria)
+	b.params.Add(newQueryOptions(opts).paramKey(operator), "("+group.String()+")")
 	return b
 }
 
-// Eq adds an equality filter condition to the request.
-func (b *FilterRequestBuilder) Eq(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "eq", value)
+// Eq adds an equality filter condition to the request. Values of every filter are formatted by type:
+// time.Time as RFC3339, nil as null, slices as arrays, PgRange as a range and fmt.Stringer with String.
+func (b *FilterRequestBuilder) Eq(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "eq", formatValue(value), opts...)
 }
 
 // Single Retrieves only one row from the result. The total result set must be one row
@@ -40,121 +66,103 @@ func (b *FilterRequestBuilder) Single() *FilterRequestBuilder {
 }
 
 // Gt adds a greater-than filter condition to the request.
-func (b *FilterRequestBuilder) Gt(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "gt", value)
+func (b *FilterRequestBuilder) Gt(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "gt", formatValue(value), opts...)
 }
 
 // Gte adds a greater-than-or-equal filter condition to the request.
-func (b *FilterRequestBuilder) Gte(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "gte", value)
+func (b *FilterRequestBuilder) Gte(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "gte", formatValue(value), opts...)
 }
 
 // Lt adds a less-than filter condition to the request.
-func (b *FilterRequestBuilder) Lt(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "lt", value)
+func (b *FilterRequestBuilder) Lt(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "lt", formatValue(value), opts...)
 }
 
 // Lte adds a less-than-or-equal filter condition to the request.
-func (b *FilterRequestBuilder) Lte(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "lte", value)
+func (b *FilterRequestBuilder) Lte(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "lte", formatValue(value), opts...)
 }
 
 // Like adds a LIKE filter condition to the request.
-func (b *FilterRequestBuilder) Like(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "like", value)
+func (b *FilterRequestBuilder) Like(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "like", formatValue(value), opts...)
 }
 
 // Ilike adds a ILIKE filter condition to the request.
-func (b *FilterRequestBuilder) Ilike(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "ilike", value)
+func (b *FilterRequestBuilder) Ilike(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "ilike", formatValue(value), opts...)
 }
 
-// Is adds an IS filter condition to the request.
-func (b *FilterRequestBuilder) Is(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "is", value)
+// Is adds an IS filter condition to the request. Value is nil, a bool or "unknown".
+func (b *FilterRequestBuilder) Is(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "is", formatValue(value), opts...)
 }
 
-// In adds an IN filter condition to the request.
-func (b *FilterRequestBuilder) In(column string, values []string) *FilterRequestBuilder {
-	sanitized := make([]string, len(values))
-	for i, value := range values {
-		sanitized[i] = value
-	}
-	return b.Filter(column, "in", fmt.Sprintf("(%s)", strings.Join(sanitized, ",")))
+// In adds an IN filter condition to the request. Values is a slice or an array.
+func (b *FilterRequestBuilder) In(column string, values any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "in", listLiteral(values), opts...)
 }
 
 // Neq adds a not-equal filter condition to the request.
-func (b *FilterRequestBuilder) Neq(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "neq", value)
+func (b *FilterRequestBuilder) Neq(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "neq", formatValue(value), opts...)
 }
 
 // Fts adds a full-text search filter condition to the request.
-func (b *FilterRequestBuilder) Fts(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "fts", value)
+func (b *FilterRequestBuilder) Fts(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "fts", formatValue(value), opts...)
 }
 
 // Plfts adds a phrase-level full-text search filter condition to the request.
-func (b *FilterRequestBuilder) Plfts(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "plfts", value)
+func (b *FilterRequestBuilder) Plfts(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "plfts", formatValue(value), opts...)
 }
 
 // Wfts adds a word-level full-text search filter condition to the request.
-func (b *FilterRequestBuilder) Wfts(column, value string) *FilterRequestBuilder {
-	return b.Filter(column, "wfts", value)
+func (b *FilterRequestBuilder) Wfts(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "wfts", formatValue(value), opts...)
 }
 
-// Cs adds a contains set filter condition to the request.
-func (b *FilterRequestBuilder) Cs(column string, values []string) *FilterRequestBuilder {
-	sanitized := make([]string, len(values))
-	for i, value := range values {
-		sanitized[i] = value
-	}
-	return b.Filter(column, "cs", fmt.Sprintf("{%s}", strings.Join(sanitized, ",")))
+// Cs adds a contains filter condition to the request. Value is a slice for arrays, a PgRange or
+// an element for ranges, or a map or struct for json.
+func (b *FilterRequestBuilder) Cs(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "cs", setLiteral(value), opts...)
 }
 
 // Cd adds a contained by set filter condition to the request.
-func (b *FilterRequestBuilder) Cd(column string, values []string) *FilterRequestBuilder {
-	sanitized := make([]string, len(values))
-	for i, value := range values {
-		sanitized[i] = value
-	}
-	return b.Filter(column, "cd", fmt.Sprintf("{%s}", strings.Join(sanitized, ",")))
+func (b *FilterRequestBuilder) Cd(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "cd", setLiteral(value), opts...)
 }
 
 // Ov adds an overlaps set filter condition to the request.
-func (b *FilterRequestBuilder) Ov(column string, values []string) *FilterRequestBuilder {
-	sanitized := make([]string, len(values))
-	for i, value := range values {
-		sanitized[i] = value
-	}
-	return b.Filter(column, "ov", fmt.Sprintf("{%s}", strings.Join(sanitized, ",")))
+func (b *FilterRequestBuilder) Ov(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "ov", setLiteral(value), opts...)
 }
 
-// Sl adds a strictly left of filter condition to the request.
-func (b *FilterRequestBuilder) Sl(column string, from, to int) *FilterRequestBuilder {
-	return b.Filter(column, "sl", fmt.Sprintf("(%d,%d)", from, to))
+// Sl adds a strictly left of filter condition to the request. From and to are exclusive bounds,
+// use Filter with PgRange for inclusive bounds.
+func (b *FilterRequestBuilder) Sl(column string, from, to any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "sl", PgRange{Lower: from, Upper: to}.String(), opts...)
 }
 
 // Sr adds a strictly right of filter condition to the request.
-func (b *FilterRequestBuilder) Sr(column string, from, to int) *FilterRequestBuilder {
-	return b.Filter(column, "sr", fmt.Sprintf("(%d,%d)", from, to))
+func (b *FilterRequestBuilder) Sr(column string, from, to any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "sr", PgRange{Lower: from, Upper: to}.String(), opts...)
 }
 
 // Nxl adds a not strictly left of filter condition to the request.
-func (b *FilterRequestBuilder) Nxl(column string, from, to int) *FilterRequestBuilder {
-	return b.Filter(column, "nxl", fmt.Sprintf("(%d,%d)", from, to))
+func (b *FilterRequestBuilder) Nxl(column string, from, to any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "nxl", PgRange{Lower: from, Upper: to}.String(), opts...)
 }
 
 // Nxr adds a not strictly right of filter condition to the request.
-func (b *FilterRequestBuilder) Nxr(column string, from, to int) *FilterRequestBuilder {
-	return b.Filter(column, "nxr", fmt.Sprintf("(%d,%d)", from, to))
+func (b *FilterRequestBuilder) Nxr(column string, from, to any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "nxr", PgRange{Lower: from, Upper: to}.String(), opts...)
 }
 
-// Ad adds an adjacent to filter condition to the request.
-func (b *FilterRequestBuilder) Ad(column string, values []string) *FilterRequestBuilder {
-	sanitized := make([]string, len(values))
-	for i, value := range values {
-		sanitized[i] = value
-	}
-	return b.Filter(column, "ad", fmt.Sprintf("{%s}", strings.Join(sanitized, ",")))
+// Ad adds an adjacent to filter condition to the request. Value is usually a PgRange.
+func (b *FilterRequestBuilder) Ad(column string, value any, opts ...QueryOption) *FilterRequestBuilder {
+	return b.Filter(column, "ad", setLiteral(value), opts...)
 }
@@ -0,0 +1,127 @@
+package supabase
+
+import "strings"
+
+// FilterGroup builds the conditions of a logical filter, e.g. `or=(status.eq.open,priority.gt.3)`.
+// Groups can be nested with Or and And.
+type FilterGroup struct {
+	conditions []string
+	negateNext bool
+}
+
+// NewFilterGroup starts a group with the given raw conditions in PostgREST syntax, e.g. "status.eq.open".
+func NewFilterGroup(conditions ...string) *FilterGroup {
+	return &FilterGroup{
+		conditions: conditions,
+	}
+}
+
+func (g *FilterGroup) String() string {
+	return strings.Join(g.conditions, ",")
+}
+
+// Not negates the next condition or nested group.
+func (g *FilterGroup) Not() *FilterGroup {
+	g.negateNext = true
+	return g
+}
+
+// Filter adds a condition to the group. The criteria is sent as is, so it must already be quoted where
+// it holds a reserved character; the typed methods such as Eq and In quote their values.
+func (g *FilterGroup) Filter(column, operator, criteria string) *FilterGroup {
+	if g.negateNext {
+		g.negateNext = false
+		operator = "not." + operator
+	}
+	g.conditions = append(g.conditions, column+"."+operator+"."+criteria)
+	return g
+}
+
+// Or adds a nested group where any condition must match.
+func (g *FilterGroup) Or(group *FilterGroup) *FilterGroup {
+	return g.nest("or", group)
+}
+
+// And adds a nested group where every condition must match.
+func (g *FilterGroup) And(group *FilterGroup) *FilterGroup {
+	return g.nest("and", group)
+}
+
+func (g *FilterGroup) nest(operator string, group *FilterGroup) *FilterGroup {
+	if g.negateNext {
+		g.negateNext = false
+		operator = "not." + operator
+	}
+	g.conditions = append(g.conditions, operator+"("+group.String()+")")
+	return g
+}
+
+// Eq adds an equality condition to the group.
+func (g *FilterGroup) Eq(column string, value any) *FilterGroup {
+	return g.Filter(column, "eq", quoteValue(formatValue(value)))
+}
+
+// Neq adds a not-equal condition to the group.
+func (g *FilterGroup) Neq(column string, value any) *FilterGroup {
+	return g.Filter(column, "neq", quoteValue(formatValue(value)))
+}
+
+// Gt adds a greater-than condition to the group.
+func (g *FilterGroup) Gt(column string, value any) *FilterGroup {
+	return g.Filter(column, "gt", quoteValue(formatValue(value)))
+}
+
+// Gte adds a greater-than-or-equal condition to the group.
+func (g *FilterGroup) Gte(column string, value any) *FilterGroup {
+	return g.Filter(column, "gte", quoteValue(formatValue(value)))
+}
+
+// Lt adds a less-than condition to the group.
+func (g *FilterGroup) Lt(column string, value any) *FilterGroup {
+	return g.Filter(column, "lt", quoteValue(formatValue(value)))
+}
+
+// Lte adds a less-than-or-equal condition to the group.
+func (g *FilterGroup) Lte(column string, value any) *FilterGroup {
+	return g.Filter(column, "lte", quoteValue(formatValue(value)))
+}
+
+// Like adds a LIKE condition to the group.
+func (g *FilterGroup) Like(column string, value any) *FilterGroup {
+	return g.Filter(column, "like", quoteValue(formatValue(value)))
+}
+
+// Ilike adds an ILIKE condition to the group.
+func (g *FilterGroup) Ilike(column string, value any) *FilterGroup {
+	return g.Filter(column, "ilike", quoteValue(formatValue(value)))
+}
+
+// Is adds an IS condition to the group.
+func (g *FilterGroup) Is(column string, value any) *FilterGroup {
+	return g.Filter(column, "is", quoteValue(formatValue(value)))
+}
+
+// In adds an IN condition to the group.
+func (g *FilterGroup) In(column string, values any) *FilterGroup {
+	return g.Filter(column, "in", listLiteral(values))
+}
+
+// Cs adds a contains set condition to the group.
+func (g *FilterGroup) Cs(column string, value any) *FilterGroup {
+	return g.Filter(column, "cs", quoteValue(setLiteral(value)))
+}
+
+// Cd adds a contained by set condition to the group.
+func (g *FilterGroup) Cd(column string, value any) *FilterGroup {
+	return g.Filter(column, "cd", quoteValue(setLiteral(value)))
+}
+
+// Ov adds an overlaps set condition to the group.
+func (g *FilterGroup) Ov(column string, value any) *FilterGroup {
+	return g.Filter(column, "ov", quoteValue(setLiteral(value)))
+}
+
+// Fts adds a full-text search condition to the group.
+func (g *FilterGroup) Fts(column string, value any) *FilterGroup {
+	return g.Filter(column, "fts", quoteValue(formatValue(value)))
+}
@@ -0,0 +1,146 @@
+package supabase
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newTestSelect() *SelectRequestBuilder {
+	return NewPostgres("test").From("todos").Select("*")
+}
+
+func TestFilterGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		group *FilterGroup
+		want  string
+	}{
+		{
+			name:  "injection",
+			group: NewFilterGroup().Eq("status", "a,b).or(id.gt.0"),
+			want:  `status.eq."a,b).or(id.gt.0"`,
+		},
+		{
+			name:  "quote and backslash",
+			group: NewFilterGroup().Eq("name", `a"b\c`),
+			want:  `name.eq."a\"b\\c"`,
+		},
+		{
+			name:  "reserved characters",
+			group: NewFilterGroup().Eq("a", "x.y").Eq("b", "x:y").Eq("c", " x "),
+			want:  `a.eq."x.y",b.eq."x:y",c.eq." x "`,
+		},
+		{
+			name:  "in list",
+			group: NewFilterGroup().In("id", []string{"x)", "y", "NULL"}),
+			want:  `id.in.("x)",y,"NULL")`,
+		},
+		{
+			name:  "contains",
+			group: NewFilterGroup().Cs("tags", []string{"a,b", "}"}),
+			want:  `tags.cs."{\"a,b\",\"}\"}"`,
+		},
+		{
+			name:  "is null",
+			group: NewFilterGroup().Is("assignee", nil),
+			want:  `assignee.is.null`,
+		},
+		{
+			name:  "nested",
+			group: NewFilterGroup().Eq("status", "open").And(NewFilterGroup().Gt("priority", 3).Not().Eq("owner", "a,b")),
+			want:  `status.eq.open,and(priority.gt.3,owner.not.eq."a,b")`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.group.String(); got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterRequestBuilderParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(q *SelectRequestBuilder)
+		key   string
+		want  string
+	}{
+		{
+			name:  "or",
+			build: func(q *SelectRequestBuilder) { q.Or(NewFilterGroup().Eq("status", "a,b).or(id.gt.0").Eq("id", 1)) },
+			key:   "or",
+			want:  `(status.eq."a,b).or(id.gt.0",id.eq.1)`,
+		},
+		{
+			name:  "not or",
+			build: func(q *SelectRequestBuilder) { q.Not().Or(NewFilterGroup().Eq("a", 1)) },
+			key:   "not.or",
+			want:  `(a.eq.1)`,
+		},
+		{
+			name:  "referenced or",
+			build: func(q *SelectRequestBuilder) { q.Or(NewFilterGroup().Eq("name", ":x"), WithReferencedTable("author")) },
+			key:   "author.or",
+			want:  `(name.eq.":x")`,
+		},
+		{
+			name:  "referenced eq",
+			build: func(q *SelectRequestBuilder) { q.Eq("name", "alice", WithReferencedTable("author")) },
+			key:   "author.name",
+			want:  `eq.alice`,
+		},
+		{
+			name:  "referenced not in",
+			build: func(q *SelectRequestBuilder) { q.Not().In("id", []int{1, 2}, WithReferencedTable("comments")) },
+			key:   "comments.id",
+			want:  `not.in.(1,2)`,
+		},
+		{
+			name:  "and",
+			build: func(q *SelectRequestBuilder) { q.And(NewFilterGroup().Eq("a", `"`).Eq("b", `\`)) },
+			key:   "and",
+			want:  `(a.eq."\"",b.eq."\\")`,
+		},
+		{
+			name:  "in",
+			build: func(q *SelectRequestBuilder) { q.In("name", []string{"a,b", `q"`, "NULL", "{}"}) },
+			key:   "name",
+			want:  `in.("a,b","q\"","NULL",{})`,
+		},
+		{
+			name:  "contains",
+			build: func(q *SelectRequestBuilder) { q.Cs("tags", []string{"a,b", "}", "NULL", " "}) },
+			key:   "tags",
+			want:  `cs.{"a,b","}","NULL"," "}`,
+		},
+		{
+			name:  "contains raw json",
+			build: func(q *SelectRequestBuilder) { q.Cs("meta", json.RawMessage(`{"a":1}`)) },
+			key:   "meta",
+			want:  `cs.{"a":1}`,
+		},
+		{
+			name:  "eq raw json",
+			build: func(q *SelectRequestBuilder) { q.Eq("meta", json.RawMessage(`{"a":1}`)) },
+			key:   "meta",
+			want:  `eq.{"a":1}`,
+		},
+		{
+			name:  "eq keeps the value in its own param",
+			build: func(q *SelectRequestBuilder) { q.Eq("status", "a,b).or(id.gt.0") },
+			key:   "status",
+			want:  `eq.a,b).or(id.gt.0`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := newTestSelect()
+			tc.build(q)
+			if got := q.params[tc.key]; len(got) != 1 || got[0] != tc.want {
+				t.Errorf("%s = %q, want %s", tc.key, got, tc.want)
+			}
+		})
+	}
+}
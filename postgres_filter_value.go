@@ -0,0 +1,222 @@
+package supabase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reserved characters refer from https://postgrest.org/en/stable/references/api/url_grammar.html#reserved-characters
+const (
+	// listReservedChars break up the values of in lists and or/and groups.
+	listReservedChars = ",.:()\"\\"
+	// arrayReservedChars break up the elements of a postgres array literal.
+	arrayReservedChars = ",{}\"\\"
+	// rangeReservedChars break up the bounds of a postgres range literal.
+	rangeReservedChars = ",()[]\"\\"
+	whitespaceChars    = " \t\n\r"
+)
+
+// PgRange is a postgres range value such as `[1,5)`. A nil bound is unbounded.
+type PgRange struct {
+	Lower          any
+	Upper          any
+	LowerInclusive bool
+	UpperInclusive bool
+}
+
+func (r PgRange) String() string {
+	var b strings.Builder
+	if r.LowerInclusive && r.Lower != nil {
+		b.WriteString("[")
+	} else {
+		b.WriteString("(")
+	}
+	if r.Lower != nil {
+		b.WriteString(quoteRangeBound(formatValue(r.Lower)))
+	}
+	b.WriteString(",")
+	if r.Upper != nil {
+		b.WriteString(quoteRangeBound(formatValue(r.Upper)))
+	}
+	if r.UpperInclusive && r.Upper != nil {
+		b.WriteString("]")
+	} else {
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// formatValue formats a filter value the way postgres reads it: nil as null, time.Time as RFC3339,
+// slices as array literals, maps as json and fmt.Stringer with its String method.
+func formatValue(value any) string {
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "null"
+		}
+		return formatValue(rv.Elem().Interface())
+	}
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case PgRange:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	}
+	if isBytes(value) {
+		// Named byte slices such as json.RawMessage hold text, not an array.
+		return string(reflect.ValueOf(value).Bytes())
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return arrayLiteral(rv)
+	case reflect.Map, reflect.Struct:
+		raw, err := json.Marshal(value)
+		if err == nil {
+			return string(raw)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// quoteValue double quotes the value when it holds a reserved character, escaping `"` and `\` with a
+// backslash, so the value is read as a single element of an in list or a logical group.
+func quoteValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, listReservedChars) && strings.TrimSpace(value) == value {
+		return value
+	}
+	return escapeQuoted(value)
+}
+
+// quoteArrayElement double quotes an element of a postgres array literal when needed, an unquoted
+// NULL being read as a null element.
+func quoteArrayElement(value string) string {
+	if value != "" && !strings.ContainsAny(value, arrayReservedChars+whitespaceChars) && !strings.EqualFold(value, "null") {
+		return value
+	}
+	return escapeQuoted(value)
+}
+
+func quoteRangeBound(value string) string {
+	if value != "" && !strings.ContainsAny(value, rangeReservedChars+whitespaceChars) {
+		return value
+	}
+	return escapeQuoted(value)
+}
+
+func escapeQuoted(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// listLiteral formats the values as an in list, e.g. `(a,"b,c",null)`. A nil value is a null element, while
+// a NULL string is quoted, so it is read as the string and not as a null element.
+func listLiteral(values any) string {
+	elements := []any{values}
+	if rv := reflect.ValueOf(values); !isBytes(values) && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+		elements = make([]any, rv.Len())
+		for i := range elements {
+			elements[i] = rv.Index(i).Interface()
+		}
+	}
+	formatted := make([]string, len(elements))
+	for i, element := range elements {
+		value := formatValue(element)
+		switch {
+		case isNil(element):
+			formatted[i] = "null"
+		case strings.EqualFold(value, "null"):
+			formatted[i] = escapeQuoted(value)
+		default:
+			formatted[i] = quoteValue(value)
+		}
+	}
+	return "(" + strings.Join(formatted, ",") + ")"
+}
+
+// isNil reports whether value is nil or a nil pointer, which formatValue formats as null.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// arrayLiteral formats a slice or array as a postgres array literal, e.g. `{a,"b,c"}`. Nested slices
+// are kept as multi dimensional arrays and nil elements as NULL.
+func arrayLiteral(rv reflect.Value) string {
+	elements := make([]string, rv.Len())
+	for i := range elements {
+		elem := rv.Index(i)
+		for elem.Kind() == reflect.Interface && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		switch {
+		case (elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface) && elem.IsNil():
+			elements[i] = "NULL"
+		case (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Type().Elem().Kind() != reflect.Uint8:
+			elements[i] = arrayLiteral(elem)
+		default:
+			elements[i] = quoteArrayElement(formatValue(elem.Interface()))
+		}
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+// setLiteral formats the value of a set operator: a slice becomes an array literal and any other value,
+// such as a PgRange or a json object, is formatted as is.
+func setLiteral(value any) string {
+	if isBytes(value) {
+		return formatValue(value)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return arrayLiteral(rv)
+	}
+	return formatValue(value)
+}
+
+// isBytes reports whether the value is a byte slice, including the named ones such as json.RawMessage.
+func isBytes(value any) bool {
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8
+}
@@ -0,0 +1,157 @@
+package supabase
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// untrustedValues are inputs which would alter the filter structure if they were sent unescaped.
+var untrustedValues = []struct {
+	name  string
+	value string
+	quote string
+	list  string
+	array string
+}{
+	{name: "plain", value: "open", quote: `open`, list: `(open)`, array: `{open}`},
+	{name: "injection", value: "a,b).or(id.gt.0", quote: `"a,b).or(id.gt.0"`, list: `("a,b).or(id.gt.0")`, array: `{"a,b).or(id.gt.0"}`},
+	{name: "double quote", value: `"`, quote: `"\""`, list: `("\"")`, array: `{"\""}`},
+	{name: "backslash", value: `\`, quote: `"\\"`, list: `("\\")`, array: `{"\\"}`},
+	{name: "quote and backslash", value: `a\",b`, quote: `"a\\\",b"`, list: `("a\\\",b")`, array: `{"a\\\",b"}`},
+	{name: "braces", value: "{}", quote: `{}`, list: `({})`, array: `{"{}"}`},
+	{name: "null", value: "NULL", quote: `NULL`, list: `("NULL")`, array: `{"NULL"}`},
+	{name: "whitespace", value: " a ", quote: `" a "`, list: `(" a ")`, array: `{" a "}`},
+	{name: "dot", value: "a.b", quote: `"a.b"`, list: `("a.b")`, array: `{a.b}`},
+	{name: "colon", value: "a:b", quote: `"a:b"`, list: `("a:b")`, array: `{a:b}`},
+	{name: "parentheses", value: "(a)", quote: `"(a)"`, list: `("(a)")`, array: `{(a)}`},
+	{name: "empty", value: "", quote: `""`, list: `("")`, array: `{""}`},
+}
+
+func TestQuoteValue(t *testing.T) {
+	for _, tc := range untrustedValues {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := quoteValue(tc.value); got != tc.quote {
+				t.Errorf("quoteValue(%q) = %s, want %s", tc.value, got, tc.quote)
+			}
+		})
+	}
+}
+
+func TestListLiteral(t *testing.T) {
+	for _, tc := range untrustedValues {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := listLiteral([]string{tc.value}); got != tc.list {
+				t.Errorf("listLiteral(%q) = %s, want %s", tc.value, got, tc.list)
+			}
+		})
+	}
+	var nilName *string
+	tests := []struct {
+		name   string
+		values interface{}
+		want   string
+	}{
+		{name: "mixed", values: []interface{}{1, "a,b"}, want: `(1,"a,b")`},
+		{name: "nil", values: []interface{}{1, nil}, want: `(1,null)`},
+		{name: "nil pointer", values: []*string{nilName}, want: `(null)`},
+		{name: "null string", values: []interface{}{"null", "NULL", nil}, want: `("null","NULL",null)`},
+		{name: "scalar", values: "a", want: `(a)`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := listLiteral(tc.values); got != tc.want {
+				t.Errorf("listLiteral(%v) = %s, want %s", tc.values, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestArrayLiteral(t *testing.T) {
+	for _, tc := range untrustedValues {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := arrayLiteral(reflect.ValueOf([]string{tc.value})); got != tc.array {
+				t.Errorf("arrayLiteral(%q) = %s, want %s", tc.value, got, tc.array)
+			}
+		})
+	}
+	tests := []struct {
+		name   string
+		values interface{}
+		want   string
+	}{
+		{name: "nested", values: [][]int{{1, 2}, {3, 4}}, want: `{{1,2},{3,4}}`},
+		{name: "nil element", values: []*string{nil}, want: `{NULL}`},
+		{name: "empty", values: []string{}, want: `{}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := arrayLiteral(reflect.ValueOf(tc.values)); got != tc.want {
+				t.Errorf("arrayLiteral = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+type testStatus int
+
+func (s testStatus) String() string {
+	return [...]string{"open", "closed"}[s]
+}
+
+func TestFormatValue(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 30, 0, 500, time.UTC)
+	name := "alice"
+	var nilName *string
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "nil", value: nil, want: `null`},
+		{name: "string", value: "a,b", want: `a,b`},
+		{name: "bool", value: true, want: `true`},
+		{name: "int", value: -3, want: `-3`},
+		{name: "uint64", value: uint64(18446744073709551615), want: `18446744073709551615`},
+		{name: "float", value: 1.5, want: `1.5`},
+		{name: "time", value: at, want: `2024-05-01T10:30:00.0000005Z`},
+		{name: "time pointer", value: &at, want: `2024-05-01T10:30:00.0000005Z`},
+		{name: "pointer", value: &name, want: `alice`},
+		{name: "nil pointer", value: nilName, want: `null`},
+		{name: "stringer", value: testStatus(1), want: `closed`},
+		{name: "bytes", value: []byte("abc"), want: `abc`},
+		{name: "raw json", value: json.RawMessage(`{"a":1}`), want: `{"a":1}`},
+		{name: "slice", value: []int{1, 2}, want: `{1,2}`},
+		{name: "map", value: map[string]int{"a": 1}, want: `{"a":1}`},
+		{name: "range", value: PgRange{Lower: 1, Upper: 5, LowerInclusive: true}, want: `[1,5)`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatValue(tc.value); got != tc.want {
+				t.Errorf("formatValue(%v) = %s, want %s", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "slice", value: []string{"a", "b c"}, want: `{a,"b c"}`},
+		{name: "raw json", value: json.RawMessage(`{"a":1}`), want: `{"a":1}`},
+		{name: "bytes", value: []byte(`["a"]`), want: `["a"]`},
+		{name: "map", value: map[string]int{"a": 1}, want: `{"a":1}`},
+		{name: "range", value: PgRange{Lower: 1, Upper: 5}, want: `(1,5)`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := setLiteral(tc.value); got != tc.want {
+				t.Errorf("setLiteral(%v) = %s, want %s", tc.value, got, tc.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package supabase
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 )
 
 type PostgresOption func(c *PostgresClient)
@@ -30,3 +35,168 @@ func WithPostgresClient(httpClient *http.Client, header map[string]string) Postg
 		c.httpClient = newRequester(httpClient, header)
 	}
 }
+
+// QueryOption tunes how a filter or a modifier is applied to a query.
+type QueryOption func(o *queryOptions)
+
+type queryOptions struct {
+	referencedTable string
+	nulls           string
+}
+
+func newQueryOptions(opts []QueryOption) queryOptions {
+	var o queryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithReferencedTable applies the filter or modifier to an embedded resource instead of the queried table.
+// Use the alias of the embedded resource when it has one.
+func WithReferencedTable(table string) QueryOption {
+	return func(o *queryOptions) {
+		o.referencedTable = table
+	}
+}
+
+// WithNullsFirst orders the null values before the other values.
+func WithNullsFirst() QueryOption {
+	return func(o *queryOptions) {
+		o.nulls = "nullsfirst"
+	}
+}
+
+// WithNullsLast orders the null values after the other values.
+func WithNullsLast() QueryOption {
+	return func(o *queryOptions) {
+		o.nulls = "nullslast"
+	}
+}
+
+// paramKey prefixes the query parameter with the referenced table, e.g. `author.or`.
+func (o queryOptions) paramKey(key string) string {
+	if o.referencedTable == "" {
+		return key
+	}
+	return o.referencedTable + "." + key
+}
+
+// WriteOption tunes how Insert and Upsert write the rows.
+type WriteOption func(o *writeOptions)
+
+type writeOptions struct {
+	onConflict       []string
+	ignoreDuplicates bool
+	defaultToNull    bool
+	columns          []string
+}
+
+func newWriteOptions(opts []WriteOption) writeOptions {
+	o := writeOptions{defaultToNull: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// OnConflict sets the columns of the unique constraint an Upsert resolves the conflicts on,
+// the primary key by default. It only applies to Upsert.
+func OnConflict(columns ...string) WriteOption {
+	return func(o *writeOptions) {
+		o.onConflict = columns
+	}
+}
+
+// IgnoreDuplicates keeps the existing rows on conflict instead of merging the new values into them.
+// It only applies to Upsert.
+func IgnoreDuplicates() WriteOption {
+	return func(o *writeOptions) {
+		o.ignoreDuplicates = true
+	}
+}
+
+// DefaultToNull sets whether the columns missing from a row are written as null, which is the default.
+// With false the missing columns take their column default, e.g. when rows of a bulk write have different
+// keys. Without Columns, the columns of a bulk write are then the union of the keys of its rows.
+func DefaultToNull(defaultToNull bool) WriteOption {
+	return func(o *writeOptions) {
+		o.defaultToNull = defaultToNull
+	}
+}
+
+// Columns restricts the written columns, so the other keys of the rows are ignored.
+func Columns(columns ...string) WriteOption {
+	return func(o *writeOptions) {
+		o.columns = columns
+	}
+}
+
+// apply sets the query parameters and preferences shared by Insert and Upsert.
+func (o writeOptions) apply(params url.Values, header http.Header, body interface{}) {
+	columns := o.columns
+	if !o.defaultToNull {
+		addPreference(header, "missing=default")
+		// PostgREST rejects rows with different keys unless the columns are set, as postgrest-js does.
+		if len(columns) == 0 {
+			columns = rowColumns(body)
+		}
+	}
+	if len(columns) > 0 {
+		quoted := make([]string, len(columns))
+		for i, column := range columns {
+			quoted[i] = quoteValue(column)
+		}
+		params.Set("columns", strings.Join(quoted, ","))
+	}
+}
+
+// rowColumns returns the union of the keys of the rows of a bulk write, in the order they first appear.
+// It is empty when the body is not a list of json objects.
+func rowColumns(body interface{}) []string {
+	if !isRowList(body) {
+		return nil
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil
+	}
+	var rows []json.RawMessage
+	if err = json.Unmarshal(raw, &rows); err != nil {
+		return nil
+	}
+	var columns []string
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		// json.Decoder reads the keys in the order of the row, which a map would lose.
+		decoder := json.NewDecoder(bytes.NewReader(row))
+		if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+			return nil
+		}
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil
+			}
+			key := token.(string)
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+			var value json.RawMessage
+			if err = decoder.Decode(&value); err != nil {
+				return nil
+			}
+		}
+	}
+	return columns
+}
+
+// isRowList reports whether the body of a write holds several rows.
+func isRowList(body interface{}) bool {
+	rv := reflect.ValueOf(body)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && !isBytes(rv.Interface())
+}
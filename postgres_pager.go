@@ -0,0 +1,247 @@
+package supabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const defaultPageSize = 100
+
+// PageOption tunes how Paginate pages through a query.
+type PageOption func(o *pageOptions)
+
+type pageOptions struct {
+	size   int
+	count  *Count
+	keyset []keysetColumn
+}
+
+type keysetColumn struct {
+	column string
+	order  Order
+}
+
+// WithPageSize sets the number of rows of a page, 100 by default. It replaces the Limit of the query.
+func WithPageSize(size int) PageOption {
+	return func(o *pageOptions) {
+		o.size = size
+	}
+}
+
+// WithPageCount requests the total row count with the first page, which is read with Pager.Total.
+func WithPageCount(count Count) PageOption {
+	return func(o *pageOptions) {
+		o.count = &count
+	}
+}
+
+// WithKeyset pages by keyset instead of offset: each page starts after the last row of the previous page,
+// which stays correct when rows are inserted or deleted while paging. Call it once per column, most
+// significant first; the columns must be selected, not null and unique together, e.g. `created_at` then `id`.
+// The keyset columns replace the order of the query.
+func WithKeyset(column string, order Order) PageOption {
+	return func(o *pageOptions) {
+		o.keyset = append(o.keyset, keysetColumn{column: column, order: order})
+	}
+}
+
+// Pager pages through the rows of a query, e.g.
+//
+//	pager := Paginate[Todo](db.From("todos").Select("*"), WithPageSize(500))
+//	for pager.Next(ctx) {
+//		process(pager.Page())
+//	}
+//	err := pager.Err()
+//
+// The query is not changed, each page is requested with a copy of it. An Offset or Range of the query sets
+// the first row of the first page, so paging starts from there, and the page size replaces its limit.
+type Pager[T any] struct {
+	query   *SelectRequestBuilder
+	options pageOptions
+	page    []T
+	err     error
+	total   int64
+	offset  int
+	cursor  map[string]interface{}
+	fetched bool
+	done    bool
+}
+
+// Paginate returns a pager of the rows of the query decoded into T.
+func Paginate[T any](query *SelectRequestBuilder, opts ...PageOption) *Pager[T] {
+	options := pageOptions{size: defaultPageSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.size <= 0 {
+		options.size = defaultPageSize
+	}
+	// The offset is always set with an int by Offset and Range, so it is a number.
+	offset, _ := strconv.Atoi(query.params.Get("offset"))
+	return &Pager[T]{
+		query:   query,
+		options: options,
+		total:   -1,
+		offset:  offset,
+	}
+}
+
+// Next fetches the next page and reports whether it has any row. It returns false when every row is
+// paged, the context is done or a request fails, which is told apart with Err.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if p.err = ctx.Err(); p.err != nil {
+		return false
+	}
+	raws, err := p.fetch(ctx)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.fetched = true
+	if len(raws) < p.options.size {
+		p.done = true
+	}
+	if len(raws) == 0 {
+		p.page = nil
+		return false
+	}
+	page := make([]T, len(raws))
+	for i, raw := range raws {
+		if err = json.Unmarshal(raw, &page[i]); err != nil {
+			p.err = err
+			return false
+		}
+	}
+	if len(p.options.keyset) > 0 {
+		if p.cursor, err = keysetCursor(raws[len(raws)-1], p.options.keyset); err != nil {
+			p.err = err
+			return false
+		}
+	}
+	p.offset += len(raws)
+	p.page = page
+	return true
+}
+
+// Page returns the rows of the current page.
+func (p *Pager[T]) Page() []T {
+	return p.page
+}
+
+// Err returns the error which stopped the pager, nil when every row is paged.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Total returns the total row count, which is -1 until the first page is fetched or when WithPageCount is not set.
+func (p *Pager[T]) Total() int64 {
+	return p.total
+}
+
+func (p *Pager[T]) fetch(ctx context.Context) ([]json.RawMessage, error) {
+	params := cloneValues(p.query.params)
+	header := p.query.header.Clone()
+	// The rows are decoded one by one, so the request never asks for a single object.
+	header.Del("Accept")
+	if p.options.count != nil && !p.fetched {
+		addPreference(header, "count="+p.options.count.String())
+	}
+	params.Set("limit", strconv.Itoa(p.options.size))
+	if len(p.options.keyset) > 0 {
+		// The cursor replaces the offset after the first page.
+		if p.fetched {
+			params.Del("offset")
+		}
+		p.applyKeyset(params)
+	} else {
+		params.Set("offset", strconv.Itoa(p.offset))
+	}
+	query := &QueryRequestBuilder{
+		client:     p.query.client,
+		params:     params,
+		header:     header,
+		path:       p.query.path,
+		httpMethod: http.MethodGet,
+	}
+	var raws []json.RawMessage
+	httpResp, err := query.execute(ctx, &raws)
+	if err != nil {
+		return nil, err
+	}
+	if p.options.count != nil && !p.fetched {
+		contentRange, err := contentRange(httpResp.Header)
+		if err != nil {
+			return nil, err
+		}
+		if contentRange != nil {
+			p.total = contentRange.Total
+		}
+	}
+	return raws, nil
+}
+
+// applyKeyset orders by the keyset columns and, after the first page, keeps the rows after the cursor,
+// e.g. `or=(created_at.gt.x,and(created_at.eq.x,id.gt.y))`.
+func (p *Pager[T]) applyKeyset(params url.Values) {
+	params.Del("order")
+	for _, k := range p.options.keyset {
+		order := k.column + "." + k.order.String()
+		if existing := params.Get("order"); existing != "" {
+			order = existing + "," + order
+		}
+		params.Set("order", order)
+	}
+	if p.cursor == nil {
+		return
+	}
+	group := NewFilterGroup()
+	for i, k := range p.options.keyset {
+		after := NewFilterGroup()
+		for _, prev := range p.options.keyset[:i] {
+			after.Eq(prev.column, p.cursor[prev.column])
+		}
+		if k.order == OrderDesc {
+			after.Lt(k.column, p.cursor[k.column])
+		} else {
+			after.Gt(k.column, p.cursor[k.column])
+		}
+		if i == 0 {
+			group.conditions = append(group.conditions, after.conditions...)
+		} else {
+			group.And(after)
+		}
+	}
+	params.Add("or", "("+group.String()+")")
+}
+
+// keysetCursor reads the keyset columns of the last row. Numbers are kept as json.Number, so large
+// integers are not rounded through float64.
+func keysetCursor(raw json.RawMessage, keyset []keysetColumn) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var row map[string]interface{}
+	if err := decoder.Decode(&row); err != nil {
+		return nil, err
+	}
+	for _, k := range keyset {
+		if value, ok := row[k.column]; !ok || value == nil {
+			return nil, ErrKeysetColumn
+		}
+	}
+	return row, nil
+}
+
+func cloneValues(values url.Values) url.Values {
+	cloned := make(url.Values, len(values))
+	for k, v := range values {
+		cloned[k] = append([]string(nil), v...)
+	}
+	return cloned
+}
@@ -0,0 +1,169 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type testPage struct {
+	body         string
+	contentRange string
+}
+
+// newTestPager serves each page request with the next page and records the query and Prefer header of
+// the requests.
+func newTestPager(t *testing.T, pages ...testPage) (*PostgresClient, *[]url.Values, *[]string) {
+	t.Helper()
+	var queries []url.Values
+	var prefers []string
+	db := newTestPostgresHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		prefers = append(prefers, r.Header.Get(headerPrefer))
+		if len(queries) > len(pages) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		page := pages[len(queries)-1]
+		if page.contentRange != "" {
+			w.Header().Set(headerContentRange, page.contentRange)
+		}
+		_, _ = w.Write([]byte(page.body))
+	})
+	return db, &queries, &prefers
+}
+
+func collectPages[T any](t *testing.T, pager *Pager[T]) [][]T {
+	t.Helper()
+	var pages [][]T
+	for pager.Next(context.Background()) {
+		pages = append(pages, pager.Page())
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return pages
+}
+
+func TestPagerOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		pages   []testPage
+		offset  int
+		offsets []string
+		rows    int
+	}{
+		{
+			name:    "short page",
+			pages:   []testPage{{body: `[{"id":1},{"id":2}]`}, {body: `[{"id":3}]`}},
+			offsets: []string{"0", "2"},
+			rows:    3,
+		},
+		{
+			name:    "empty page",
+			pages:   []testPage{{body: `[{"id":1},{"id":2}]`}, {body: `[]`}},
+			offsets: []string{"0", "2"},
+			rows:    2,
+		},
+		{
+			name:    "query offset",
+			pages:   []testPage{{body: `[{"id":11},{"id":12}]`}, {body: `[]`}},
+			offset:  10,
+			offsets: []string{"10", "12"},
+			rows:    2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, queries, _ := newTestPager(t, tc.pages...)
+			query := db.From("todos").Select("id").Limit(50)
+			if tc.offset > 0 {
+				query.Offset(tc.offset)
+			}
+			pages := collectPages(t, Paginate[testTodo](query, WithPageSize(2)))
+			var rows int
+			for _, page := range pages {
+				rows += len(page)
+			}
+			if rows != tc.rows {
+				t.Errorf("paged %d rows, want %d", rows, tc.rows)
+			}
+			var offsets []string
+			for _, q := range *queries {
+				offsets = append(offsets, q.Get("offset"))
+				if q.Get("limit") != "2" {
+					t.Errorf("limit = %s, want the page size", q.Get("limit"))
+				}
+			}
+			if !reflect.DeepEqual(offsets, tc.offsets) {
+				t.Errorf("offsets = %v, want %v", offsets, tc.offsets)
+			}
+			if query.params.Get("limit") != "50" {
+				t.Errorf("the query is changed to %s", query.params.Encode())
+			}
+		})
+	}
+}
+
+func TestPagerKeyset(t *testing.T) {
+	db, queries, _ := newTestPager(t,
+		testPage{body: `[{"id":1,"created_at":"2024-01-01"},{"id":2,"created_at":"2024-01-02"}]`},
+		testPage{body: `[{"id":3,"created_at":"2024-01-02"},{"id":9007199254740993,"created_at":"2024-01-03"}]`},
+		testPage{body: `[]`},
+	)
+	query := db.From("todos").Select("id", "created_at").Order("task", OrderAsc).Offset(5)
+	pages := collectPages(t, Paginate[map[string]interface{}](query,
+		WithPageSize(2),
+		WithKeyset("created_at", OrderDesc),
+		WithKeyset("id", OrderAsc),
+	))
+	if len(pages) != 2 {
+		t.Fatalf("paged %d pages, want 2", len(pages))
+	}
+	wantOr := []string{
+		"",
+		"(created_at.lt.2024-01-02,and(created_at.eq.2024-01-02,id.gt.2))",
+		"(created_at.lt.2024-01-03,and(created_at.eq.2024-01-03,id.gt.9007199254740993))",
+	}
+	wantOffset := []string{"5", "", ""}
+	for i, q := range *queries {
+		if got := q.Get("order"); got != "created_at.desc,id.asc" {
+			t.Errorf("page %d order = %s", i, got)
+		}
+		if got := q.Get("or"); got != wantOr[i] {
+			t.Errorf("page %d or = %s, want %s", i, got, wantOr[i])
+		}
+		if got := q.Get("offset"); got != wantOffset[i] {
+			t.Errorf("page %d offset = %s, want %s", i, got, wantOffset[i])
+		}
+	}
+}
+
+func TestPagerKeysetShortPage(t *testing.T) {
+	db, queries, _ := newTestPager(t, testPage{body: `[{"id":1}]`})
+	pages := collectPages(t, Paginate[testTodo](db.From("todos").Select("id"), WithPageSize(2), WithKeyset("id", OrderAsc)))
+	if len(pages) != 1 || len(*queries) != 1 {
+		t.Errorf("paged %d pages with %d requests, want 1", len(pages), len(*queries))
+	}
+}
+
+func TestPagerCount(t *testing.T) {
+	db, _, prefers := newTestPager(t,
+		testPage{body: `[{"id":1},{"id":2}]`, contentRange: "0-1/3"},
+		testPage{body: `[{"id":3}]`, contentRange: "2-2/*"},
+	)
+	pager := Paginate[testTodo](db.From("todos").Select("id"), WithPageSize(2), WithPageCount(CountExact))
+	if pager.Total() != -1 {
+		t.Errorf("total before the first page = %d, want -1", pager.Total())
+	}
+	collectPages(t, pager)
+	if pager.Total() != 3 {
+		t.Errorf("total = %d, want 3", pager.Total())
+	}
+	if want := []string{"count=exact", ""}; !reflect.DeepEqual(*prefers, want) {
+		t.Errorf("prefer = %q, want %q", *prefers, want)
+	}
+}
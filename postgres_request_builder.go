@@ -33,11 +33,15 @@ func (b *RequestBuilder) Select(columns ...string) *SelectRequestBuilder {
 }
 
 // Insert starts building an INSERT request with the provided JSON data.
-func (b *RequestBuilder) Insert(json interface{}) *QueryRequestBuilder {
+// DefaultToNull and Columns apply to Insert, the conflict options only to Upsert.
+func (b *RequestBuilder) Insert(json interface{}, opts ...WriteOption) *QueryRequestBuilder {
+	newWriteOptions(opts).apply(b.params, b.header, json)
 	// Return result after insert
-	b.header.Set("Prefer", "return=representation")
-	// Return single instead of array after insert
-	b.header.Set("Accept", "application/vnd.pgrst.object+json")
+	addPreference(b.header, preferReturnRep)
+	// Return single instead of array after insert, unless several rows are inserted
+	if !isRowList(json) {
+		b.header.Set("Accept", "application/vnd.pgrst.object+json")
+	}
 	return &QueryRequestBuilder{
 		client:     b.client,
 		path:       b.path,
@@ -48,9 +52,20 @@ func (b *RequestBuilder) Insert(json interface{}) *QueryRequestBuilder {
 	}
 }
 
-// Upsert starts building an UPSERT request with the provided JSON data.
-func (b *RequestBuilder) Upsert(json interface{}) *QueryRequestBuilder {
-	b.header.Set("Prefer", "return=representation,resolution=merge-duplicates")
+// Upsert starts building an UPSERT request with the provided JSON data. The conflicting rows are merged
+// unless IgnoreDuplicates is set, and OnConflict resolves the conflicts on a unique constraint other than the primary key.
+func (b *RequestBuilder) Upsert(json interface{}, opts ...WriteOption) *QueryRequestBuilder {
+	o := newWriteOptions(opts)
+	o.apply(b.params, b.header, json)
+	addPreference(b.header, preferReturnRep)
+	if o.ignoreDuplicates {
+		addPreference(b.header, "resolution=ignore-duplicates")
+	} else {
+		addPreference(b.header, "resolution=merge-duplicates")
+	}
+	if len(o.onConflict) > 0 {
+		b.params.Set("on_conflict", strings.Join(o.onConflict, ","))
+	}
 	return &QueryRequestBuilder{
 		client:     b.client,
 		path:       b.path,
@@ -63,7 +78,7 @@ func (b *RequestBuilder) Upsert(json interface{}) *QueryRequestBuilder {
 
 // Update starts building an UPDATE request with the provided JSON data.
 func (b *RequestBuilder) Update(json interface{}) *FilterRequestBuilder {
-	b.header.Set("Prefer", "return=representation")
+	addPreference(b.header, preferReturnRep)
 	return &FilterRequestBuilder{
 		QueryRequestBuilder: QueryRequestBuilder{
 			client:     b.client,
@@ -104,6 +119,31 @@ type QueryRequestBuilder struct {
 
 // Execute sends the query request with the provided context and unmarshal the response JSON into the provided object.
 func (b *QueryRequestBuilder) Execute(ctx context.Context, result interface{}) error {
+	_, err := b.execute(ctx, result)
+	return err
+}
+
+// ExecuteWithCount works like Execute and returns the parsed Content-Range header, which holds the total
+// row count when Count is set. The range is nil when the response has no Content-Range header.
+func (b *QueryRequestBuilder) ExecuteWithCount(ctx context.Context, result interface{}) (*ContentRange, error) {
+	httpResp, err := b.execute(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	return contentRange(httpResp.Header)
+}
+
+// ExecuteWithResponse works like Execute and returns the status code, headers and raw body of the response,
+// e.g. to tell a 201 from a 200 or to read the Location of an inserted row.
+func (b *QueryRequestBuilder) ExecuteWithResponse(ctx context.Context, result interface{}) (*PostgresResponse, error) {
+	httpResp, err := b.execute(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	return newPostgresResponse(httpResp)
+}
+
+func (b *QueryRequestBuilder) execute(ctx context.Context, result interface{}) (*Resp, error) {
 	fullUrl := b.client.baseURL
 	fullUrl.Path += b.path
 	fullUrl.RawQuery = b.params.Encode()
@@ -114,27 +154,23 @@ func (b *QueryRequestBuilder) Execute(ctx context.Context, result interface{}) e
 			}
 		}
 		if result == nil {
+			// Only the representation is dropped, so a requested count is still returned.
 			req.Header.Set("Accept", "")
-			req.Header.Set("Prefer", "")
+			removePreference(req.Header, preferReturnRep)
 		}
 	})
 	if err != nil {
 		logger.Error("failed in httpclient call with err: %s", err)
-		return err
+		return nil, err
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in execute with context due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		var reqError PostgresError
-		if err = json.Unmarshal(httpResp.Body.Bytes(), &reqError); err != nil {
-			return err
-		}
-		reqError.HTTPStatusCode = httpResp.StatusCode
-		return &reqError
+		return nil, postgresError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
-	if httpResp.StatusCode != http.StatusNoContent && result != nil {
+	if httpResp.StatusCode != http.StatusNoContent && b.httpMethod != http.MethodHead && result != nil {
 		if err = json.Unmarshal(httpResp.Body.Bytes(), result); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return nil
+	return httpResp, nil
 }
@@ -0,0 +1,102 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type testUser struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+func TestUpsertOptions(t *testing.T) {
+	db, requests := newTestPostgres(t, `[]`)
+	rows := []interface{}{
+		testUser{Email: "a@example.com", Name: "a"},
+		map[string]interface{}{"email": "b@example.com", "team": "x"},
+	}
+	var result []map[string]interface{}
+	err := db.From("users").Upsert(rows,
+		OnConflict("email"),
+		IgnoreDuplicates(),
+		DefaultToNull(false),
+	).Execute(context.Background(), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := (*requests)[0]
+	if req.method != http.MethodPost {
+		t.Errorf("method = %s", req.method)
+	}
+	if got, want := req.body, `[{"email":"a@example.com","name":"a"},{"email":"b@example.com","team":"x"}]`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+	if got, want := req.query.Get("on_conflict"), "email"; got != want {
+		t.Errorf("on_conflict = %s, want %s", got, want)
+	}
+	if got, want := req.query.Get("columns"), "email,name,team"; got != want {
+		t.Errorf("columns = %s, want %s", got, want)
+	}
+	if got, want := req.header.Get("Prefer"), "resolution=ignore-duplicates,return=representation,missing=default"; got != want {
+		t.Errorf("prefer = %s, want %s", got, want)
+	}
+}
+
+func TestInsertOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    interface{}
+		opts    []WriteOption
+		columns string
+		prefer  string
+		accept  string
+	}{
+		{
+			name:   "single row",
+			body:   testUser{Email: "a@example.com"},
+			prefer: "return=representation",
+			accept: "application/vnd.pgrst.object+json",
+		},
+		{
+			name:    "explicit columns",
+			body:    testUser{Email: "a@example.com", Name: "a"},
+			opts:    []WriteOption{Columns("email")},
+			columns: "email",
+			prefer:  "return=representation",
+			accept:  "application/vnd.pgrst.object+json",
+		},
+		{
+			name:    "bulk with defaults",
+			body:    []map[string]interface{}{{"email": "a@example.com"}, {"name": "b"}},
+			opts:    []WriteOption{DefaultToNull(false)},
+			columns: "email,name",
+			prefer:  "return=representation,missing=default",
+			accept:  applicationJSON,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			response := `{}`
+			if isRowList(tc.body) {
+				response = `[]`
+			}
+			db, requests := newTestPostgres(t, response)
+			var result interface{}
+			if err := db.From("users").Insert(tc.body, tc.opts...).Execute(context.Background(), &result); err != nil {
+				t.Fatal(err)
+			}
+			req := (*requests)[0]
+			if got := req.query.Get("columns"); got != tc.columns {
+				t.Errorf("columns = %s, want %s", got, tc.columns)
+			}
+			if got := req.header.Get("Prefer"); got != tc.prefer {
+				t.Errorf("prefer = %s, want %s", got, tc.prefer)
+			}
+			if got := req.header.Get("Accept"); got != tc.accept {
+				t.Errorf("accept = %s, want %s", got, tc.accept)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package supabase
+
+import (
+	"net/http"
+	"strings"
+)
+
+// PostgresResponse is the metadata of a PostgREST response, returned alongside the decoded result by ExecuteWithResponse.
+type PostgresResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body, which is empty for HEAD requests.
+	Body []byte
+	// ContentRange is nil when the response has no Content-Range header.
+	ContentRange *ContentRange
+	// PreferenceApplied lists the preferences of the Prefer header which PostgREST applied, e.g. `count=exact`.
+	PreferenceApplied []string
+	// Location is the url of the inserted row, when PostgREST returns it.
+	Location string
+}
+
+func newPostgresResponse(httpResp *Resp) (*PostgresResponse, error) {
+	contentRange, err := contentRange(httpResp.Header)
+	if err != nil {
+		return nil, err
+	}
+	var applied []string
+	for _, value := range httpResp.Header.Values("Preference-Applied") {
+		for _, p := range strings.Split(value, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				applied = append(applied, p)
+			}
+		}
+	}
+	return &PostgresResponse{
+		StatusCode:        httpResp.StatusCode,
+		Header:            httpResp.Header,
+		Body:              httpResp.Body.Bytes(),
+		ContentRange:      contentRange,
+		PreferenceApplied: applied,
+		Location:          httpResp.Header.Get("Location"),
+	}, nil
+}
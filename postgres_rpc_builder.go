@@ -1,9 +1,11 @@
 package supabase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 )
 
 type RpcRequestBuilder struct {
@@ -28,10 +30,59 @@ func (c *PostgresClient) RPC(f string, params interface{}, opts ...HeaderOption)
 	}
 }
 
+// Count requests the number of rows returned by a set returning function, which is returned in the
+// Content-Range header and read with ExecuteWithCount.
+func (r *RpcRequestBuilder) Count(count Count) *RpcRequestBuilder {
+	addPreference(r.header, "count="+count.String())
+	return r
+}
+
+// Head calls the function with HEAD, so only the headers are returned. The params are sent in the query
+// string, which requires the function to be immutable or stable.
+func (r *RpcRequestBuilder) Head() *RpcRequestBuilder {
+	r.httpMethod = http.MethodHead
+	return r
+}
+
 func (r *RpcRequestBuilder) Execute(ctx context.Context, result interface{}) error {
+	_, err := r.execute(ctx, result)
+	return err
+}
+
+// ExecuteWithCount works like Execute and returns the parsed Content-Range header, which holds the total
+// row count when Count is set. The range is nil when the response has no Content-Range header.
+func (r *RpcRequestBuilder) ExecuteWithCount(ctx context.Context, result interface{}) (*ContentRange, error) {
+	httpResp, err := r.execute(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	return contentRange(httpResp.Header)
+}
+
+// ExecuteWithResponse works like Execute and returns the status code, headers and raw body of the response,
+// e.g. to tell a 201 from a 200 or to read the Location of an inserted row.
+func (r *RpcRequestBuilder) ExecuteWithResponse(ctx context.Context, result interface{}) (*PostgresResponse, error) {
+	httpResp, err := r.execute(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	return newPostgresResponse(httpResp)
+}
+
+func (r *RpcRequestBuilder) execute(ctx context.Context, result interface{}) (*Resp, error) {
 	fullUrl := r.client.baseURL
 	fullUrl.Path += r.path
-	httpResp, err := r.client.httpClient.Call(ctx, fullUrl.String(), r.httpMethod, r.params, func(req *http.Request) {
+	body := r.params
+	if r.httpMethod == http.MethodHead {
+		qs, err := rpcQuery(r.params)
+		if err != nil {
+			logger.Error("failed in build rpc query with err: %s", err)
+			return nil, err
+		}
+		fullUrl.RawQuery = qs.Encode()
+		body = nil
+	}
+	httpResp, err := r.client.httpClient.Call(ctx, fullUrl.String(), r.httpMethod, body, func(req *http.Request) {
 		for k, values := range r.header {
 			for i := range values {
 				req.Header.Set(k, values[i])
@@ -40,21 +91,39 @@ func (r *RpcRequestBuilder) Execute(ctx context.Context, result interface{}) err
 	})
 	if err != nil {
 		logger.Error("failed in httpclient call with err: %s", err)
-		return err
+		return nil, err
 	}
 	if !isHTTPSuccess(httpResp.StatusCode) {
 		logger.Warn("getting %d in sign in with password due to err: %s", httpResp.StatusCode, httpResp.Body.String())
-		var reqError PostgresError
-		if err = json.Unmarshal(httpResp.Body.Bytes(), &reqError); err != nil {
-			return err
-		}
-		reqError.HTTPStatusCode = httpResp.StatusCode
-		return &reqError
+		return nil, postgresError(httpResp.Body.Bytes(), httpResp.StatusCode)
 	}
-	if httpResp.StatusCode != http.StatusNoContent && result != nil {
+	if httpResp.StatusCode != http.StatusNoContent && r.httpMethod != http.MethodHead && result != nil {
 		if err = json.Unmarshal(httpResp.Body.Bytes(), result); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return nil
+	return httpResp, nil
+}
+
+// rpcQuery converts the params into query parameters, the way PostgREST reads the arguments of a GET or HEAD call.
+func rpcQuery(params interface{}) (url.Values, error) {
+	qs := make(url.Values)
+	if params == nil {
+		return qs, nil
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	// Numbers are kept as json.Number, so large integers are not rounded through float64.
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var args map[string]interface{}
+	if err = decoder.Decode(&args); err != nil {
+		return nil, err
+	}
+	for name, value := range args {
+		qs.Set(name, formatValue(value))
+	}
+	return qs, nil
 }
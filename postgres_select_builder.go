@@ -2,6 +2,7 @@ package supabase
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 )
 
@@ -11,27 +12,56 @@ type SelectRequestBuilder struct {
 	FilterRequestBuilder
 }
 
-// Order sets the ordering column and direction for the SELECT request.
-func (b *SelectRequestBuilder) Order(column string, order Order) *SelectRequestBuilder {
-	b.params.Set("order", column+"."+order.String())
+// Count requests the number of rows matched by the SELECT request, which is returned in the Content-Range
+// header and read with ExecuteWithCount.
+func (b *SelectRequestBuilder) Count(count Count) *SelectRequestBuilder {
+	addPreference(b.header, "count="+count.String())
 	return b
 }
 
-// Range sets the range of rows to be returned for the SELECT request. Range is consist of offset and limit
-func (b *SelectRequestBuilder) Range(from, to int) *SelectRequestBuilder {
-	b.params.Set("offset", fmt.Sprintf("%d", from))
-	b.params.Set("limit", fmt.Sprintf("%d", to-from+1))
+// Head sends the SELECT request as HEAD, so only the headers are returned. Combined with Count it
+// gets the row count without fetching the rows.
+func (b *SelectRequestBuilder) Head() *SelectRequestBuilder {
+	b.httpMethod = http.MethodHead
 	return b
 }
 
-// Limit the query result by `count`.
-func (b *SelectRequestBuilder) Limit(count int) *SelectRequestBuilder {
-	b.params.Set("limit", strconv.Itoa(count))
+// Order adds an ordering column and direction for the SELECT request. Columns are ordered in call order,
+// e.g. `order=priority.desc,created_at.asc`. WithNullsFirst or WithNullsLast places the null values and
+// WithReferencedTable orders the rows of an embedded resource instead. Use JSONPath to order on a json field.
+func (b *SelectRequestBuilder) Order(column string, order Order, opts ...QueryOption) *SelectRequestBuilder {
+	o := newQueryOptions(opts)
+	term := column + "." + order.String()
+	if o.nulls != "" {
+		term += "." + o.nulls
+	}
+	key := o.paramKey("order")
+	if existing := b.params.Get(key); existing != "" {
+		term = existing + "," + term
+	}
+	b.params.Set(key, term)
 	return b
 }
 
-// Offset skips specified number of rows.
-func (b *SelectRequestBuilder) Offset(number int) *SelectRequestBuilder {
-	b.params.Set("offset", strconv.Itoa(number))
+// Range sets the range of rows to be returned for the SELECT request. Range is consist of offset and limit.
+// WithReferencedTable limits the rows of an embedded resource instead.
+func (b *SelectRequestBuilder) Range(from, to int, opts ...QueryOption) *SelectRequestBuilder {
+	o := newQueryOptions(opts)
+	b.params.Set(o.paramKey("offset"), fmt.Sprintf("%d", from))
+	b.params.Set(o.paramKey("limit"), fmt.Sprintf("%d", to-from+1))
+	return b
+}
+
+// Limit the query result by `count`. WithReferencedTable limits the rows of an embedded resource instead.
+func (b *SelectRequestBuilder) Limit(count int, opts ...QueryOption) *SelectRequestBuilder {
+	o := newQueryOptions(opts)
+	b.params.Set(o.paramKey("limit"), strconv.Itoa(count))
+	return b
+}
+
+// Offset skips specified number of rows. WithReferencedTable skips the rows of an embedded resource instead.
+func (b *SelectRequestBuilder) Offset(number int, opts ...QueryOption) *SelectRequestBuilder {
+	o := newQueryOptions(opts)
+	b.params.Set(o.paramKey("offset"), strconv.Itoa(number))
 	return b
 }
@@ -0,0 +1,94 @@
+package supabase
+
+import "strings"
+
+// Resource embedding refer from https://postgrest.org/en/stable/references/api/resource_embedding.html
+
+// EmbedExpr is an embedded resource of a select, e.g. `author:users!posts_author_fkey!inner(name,email)`.
+// Pass its String to Select, or to the columns of another EmbedExpr to nest it.
+type EmbedExpr struct {
+	resource string
+	columns  []string
+	alias    string
+	hint     string
+	join     string
+	spread   bool
+}
+
+// Embed embeds the related resource with the specified columns, all columns when none is given.
+func Embed(resource string, columns ...string) *EmbedExpr {
+	return &EmbedExpr{
+		resource: resource,
+		columns:  columns,
+	}
+}
+
+// As renames the embedded resource in the result. Filters and modifiers of the embed refer to the alias.
+func (e *EmbedExpr) As(alias string) *EmbedExpr {
+	e.alias = alias
+	return e
+}
+
+// Hint disambiguates the relationship with the foreign key or the column to embed on, e.g. `posts_author_fkey`.
+func (e *EmbedExpr) Hint(hint string) *EmbedExpr {
+	e.hint = hint
+	return e
+}
+
+// Inner embeds with an inner join, so the rows without a related row are filtered out.
+func (e *EmbedExpr) Inner() *EmbedExpr {
+	e.join = "inner"
+	return e
+}
+
+// Left embeds with a left join, which is the default of PostgREST.
+func (e *EmbedExpr) Left() *EmbedExpr {
+	e.join = "left"
+	return e
+}
+
+// Spread lifts the columns of a to-one embedded resource into the parent row, e.g. `...profile(name)`.
+func (e *EmbedExpr) Spread() *EmbedExpr {
+	e.spread = true
+	return e
+}
+
+func (e *EmbedExpr) String() string {
+	var b strings.Builder
+	if e.spread {
+		b.WriteString("...")
+	}
+	if e.alias != "" {
+		b.WriteString(e.alias + ":")
+	}
+	b.WriteString(e.resource)
+	if e.hint != "" {
+		b.WriteString("!" + e.hint)
+	}
+	if e.join != "" {
+		b.WriteString("!" + e.join)
+	}
+	columns := e.columns
+	if len(columns) == 0 {
+		columns = []string{"*"}
+	}
+	b.WriteString("(" + strings.Join(columns, ",") + ")")
+	return b.String()
+}
+
+// JSONPath refers to a field of a json column, e.g. `JSONPath("address", "city")` is `address->city`, so it can be
+// selected, filtered or ordered on. Array elements are referred by index, e.g. "0".
+func JSONPath(column string, keys ...string) string {
+	if len(keys) == 0 {
+		return column
+	}
+	return column + "->" + strings.Join(keys, "->")
+}
+
+// JSONTextPath is like JSONPath, but reads the last field as text, e.g. `address->>city`.
+func JSONTextPath(column string, keys ...string) string {
+	if len(keys) == 0 {
+		return column
+	}
+	return JSONPath(column, keys[:len(keys)-1]...) + "->>" + keys[len(keys)-1]
+}
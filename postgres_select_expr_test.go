@@ -0,0 +1,59 @@
+package supabase
+
+import "testing"
+
+func TestEmbedExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr *EmbedExpr
+		want string
+	}{
+		{name: "all columns", expr: Embed("comments"), want: `comments(*)`},
+		{name: "columns", expr: Embed("comments", "id", "body"), want: `comments(id,body)`},
+		{name: "alias", expr: Embed("users", "name").As("author"), want: `author:users(name)`},
+		{name: "hint", expr: Embed("users", "name").Hint("posts_author_fkey"), want: `users!posts_author_fkey(name)`},
+		{name: "inner", expr: Embed("users", "name").Inner(), want: `users!inner(name)`},
+		{name: "left", expr: Embed("users", "name").Left(), want: `users!left(name)`},
+		{
+			name: "alias hint and inner",
+			expr: Embed("users", "name", "email").As("author").Hint("posts_author_fkey").Inner(),
+			want: `author:users!posts_author_fkey!inner(name,email)`,
+		},
+		{name: "spread", expr: Embed("profiles", "name").Spread(), want: `...profiles(name)`},
+		{
+			name: "nested",
+			expr: Embed("comments", "body", Embed("users", "name").As("author").String()),
+			want: `comments(body,author:users(name))`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.expr.String(); got != tc.want {
+				t.Errorf("String() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "column", got: JSONPath("address"), want: `address`},
+		{name: "field", got: JSONPath("address", "city"), want: `address->city`},
+		{name: "nested", got: JSONPath("address", "geo", "lat"), want: `address->geo->lat`},
+		{name: "array index", got: JSONPath("tags", "0"), want: `tags->0`},
+		{name: "text column", got: JSONTextPath("address"), want: `address`},
+		{name: "text field", got: JSONTextPath("address", "city"), want: `address->>city`},
+		{name: "text nested", got: JSONTextPath("address", "geo", "lat"), want: `address->geo->>lat`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Errorf("got %s, want %s", tc.got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,469 @@
+package supabase
+
+import (
+	"context"
+)
+
+// TableNamer is implemented by the row types which know their table, so Table can infer the table name.
+type TableNamer interface {
+	TableName() string
+}
+
+// TypedTable is a table whose rows are decoded into T, instead of the interface{} results of From.
+type TypedTable[T any] struct {
+	db    PostgresAPI
+	table string
+	opts  []HeaderOption
+}
+
+// Table returns the typed table of T. When table is empty, the name is inferred from the TableName method
+// of T, which may be declared on T or *T, and ErrTableName is returned when there is none.
+// The header options, e.g. AuthToken, apply to every request.
+func Table[T any](db PostgresAPI, table string, opts ...HeaderOption) (*TypedTable[T], error) {
+	if table == "" {
+		var row T
+		if namer, ok := any(row).(TableNamer); ok {
+			table = namer.TableName()
+		} else if namer, ok := any(&row).(TableNamer); ok {
+			table = namer.TableName()
+		}
+	}
+	if table == "" {
+		return nil, ErrTableName
+	}
+	return &TypedTable[T]{
+		db:    db,
+		table: table,
+		opts:  opts,
+	}, nil
+}
+
+func (t *TypedTable[T]) from() *RequestBuilder {
+	return t.db.From(t.table, t.opts...)
+}
+
+// Select starts building a SELECT request with the specified columns, all columns when none is given.
+func (t *TypedTable[T]) Select(columns ...string) *TypedSelect[T] {
+	if len(columns) == 0 {
+		columns = []string{"*"}
+	}
+	return &TypedSelect[T]{SelectRequestBuilder: t.from().Select(columns...)}
+}
+
+// Insert inserts the row and returns the inserted row.
+func (t *TypedTable[T]) Insert(ctx context.Context, row T, opts ...WriteOption) (T, error) {
+	var result T
+	err := t.from().Insert(row, opts...).Execute(ctx, &result)
+	return result, err
+}
+
+// Upsert inserts the row, or merges it into the row with the same primary key, and returns the stored row.
+// With IgnoreDuplicates a conflicting row is not returned, so the zero T is returned for it.
+func (t *TypedTable[T]) Upsert(ctx context.Context, row T, opts ...WriteOption) (T, error) {
+	var result T
+	// The rows are read as an array, which is empty when IgnoreDuplicates skips the row.
+	var rows []T
+	if err := t.from().Upsert(row, opts...).Execute(ctx, &rows); err != nil {
+		return result, err
+	}
+	if len(rows) > 0 {
+		result = rows[0]
+	}
+	return result, nil
+}
+
+// Update starts building an UPDATE request with the provided values, which may be a partial row such as a map.
+func (t *TypedTable[T]) Update(values interface{}) *TypedFilter[T] {
+	return &TypedFilter[T]{FilterRequestBuilder: t.from().Update(values)}
+}
+
+// Delete starts building a DELETE request, which returns the deleted rows.
+func (t *TypedTable[T]) Delete() *TypedFilter[T] {
+	query := t.from().Delete()
+	addPreference(query.header, preferReturnRep)
+	return &TypedFilter[T]{FilterRequestBuilder: query}
+}
+
+// TypedSelect is a SELECT request of a TypedTable.
+type TypedSelect[T any] struct {
+	*SelectRequestBuilder
+}
+
+// All returns the selected rows.
+func (s *TypedSelect[T]) All(ctx context.Context) ([]T, error) {
+	return executeAll[T](ctx, &s.FilterRequestBuilder)
+}
+
+// One returns the single selected row. An error is returned when the request matches no row or more than one.
+func (s *TypedSelect[T]) One(ctx context.Context) (T, error) {
+	return executeOne[T](ctx, &s.FilterRequestBuilder)
+}
+
+// TypedFilter is an UPDATE or DELETE request of a TypedTable.
+type TypedFilter[T any] struct {
+	*FilterRequestBuilder
+}
+
+// All executes the request and returns the changed rows.
+func (f *TypedFilter[T]) All(ctx context.Context) ([]T, error) {
+	return executeAll[T](ctx, f.FilterRequestBuilder)
+}
+
+// One executes the request and returns the single changed row. An error is returned when the request
+// changes no row or more than one, in which case the change is not applied.
+func (f *TypedFilter[T]) One(ctx context.Context) (T, error) {
+	return executeOne[T](ctx, f.FilterRequestBuilder)
+}
+
+func executeAll[T any](ctx context.Context, query *FilterRequestBuilder) ([]T, error) {
+	var rows []T
+	if err := query.Execute(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func executeOne[T any](ctx context.Context, query *FilterRequestBuilder) (T, error) {
+	var row T
+	err := query.Single().Execute(ctx, &row)
+	return row, err
+}
+
+// The typed builders wrap the filters and modifiers, so they return the typed builder and a query can be
+// chained up to All or One, e.g. `todos.Select().Eq("done", false).Order("id", OrderAsc).All(ctx)`.
+
+// Not negates the next filter condition, like SelectRequestBuilder.Not.
+func (q *TypedSelect[T]) Not() *TypedSelect[T] {
+	q.SelectRequestBuilder.Not()
+	return q
+}
+
+// Count requests the number of matched rows, read with ExecuteWithCount, like SelectRequestBuilder.Count.
+func (q *TypedSelect[T]) Count(count Count) *TypedSelect[T] {
+	q.SelectRequestBuilder.Count(count)
+	return q
+}
+
+// Filter adds a raw filter condition, like SelectRequestBuilder.Filter.
+func (q *TypedSelect[T]) Filter(column, operator, criteria string, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Filter(column, operator, criteria, opts...)
+	return q
+}
+
+// Or adds a group of conditions where any condition must match, like SelectRequestBuilder.Or.
+func (q *TypedSelect[T]) Or(group *FilterGroup, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Or(group, opts...)
+	return q
+}
+
+// And adds a group of conditions where every condition must match, like SelectRequestBuilder.And.
+func (q *TypedSelect[T]) And(group *FilterGroup, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.And(group, opts...)
+	return q
+}
+
+// Eq adds an equality filter, like SelectRequestBuilder.Eq.
+func (q *TypedSelect[T]) Eq(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Eq(column, value, opts...)
+	return q
+}
+
+// Neq adds a not equal filter, like SelectRequestBuilder.Neq.
+func (q *TypedSelect[T]) Neq(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Neq(column, value, opts...)
+	return q
+}
+
+// Gt adds a greater than filter, like SelectRequestBuilder.Gt.
+func (q *TypedSelect[T]) Gt(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Gt(column, value, opts...)
+	return q
+}
+
+// Gte adds a greater than or equal filter, like SelectRequestBuilder.Gte.
+func (q *TypedSelect[T]) Gte(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Gte(column, value, opts...)
+	return q
+}
+
+// Lt adds a less than filter, like SelectRequestBuilder.Lt.
+func (q *TypedSelect[T]) Lt(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Lt(column, value, opts...)
+	return q
+}
+
+// Lte adds a less than or equal filter, like SelectRequestBuilder.Lte.
+func (q *TypedSelect[T]) Lte(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Lte(column, value, opts...)
+	return q
+}
+
+// Like adds a case sensitive pattern filter, like SelectRequestBuilder.Like.
+func (q *TypedSelect[T]) Like(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Like(column, value, opts...)
+	return q
+}
+
+// Ilike adds a case insensitive pattern filter, like SelectRequestBuilder.Ilike.
+func (q *TypedSelect[T]) Ilike(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Ilike(column, value, opts...)
+	return q
+}
+
+// Is adds an is filter, e.g. for null, like SelectRequestBuilder.Is.
+func (q *TypedSelect[T]) Is(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Is(column, value, opts...)
+	return q
+}
+
+// In adds an in list filter, like SelectRequestBuilder.In.
+func (q *TypedSelect[T]) In(column string, values any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.In(column, values, opts...)
+	return q
+}
+
+// Fts adds a full text search filter, like SelectRequestBuilder.Fts.
+func (q *TypedSelect[T]) Fts(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Fts(column, value, opts...)
+	return q
+}
+
+// Plfts adds a plain full text search filter, like SelectRequestBuilder.Plfts.
+func (q *TypedSelect[T]) Plfts(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Plfts(column, value, opts...)
+	return q
+}
+
+// Wfts adds a web search full text search filter, like SelectRequestBuilder.Wfts.
+func (q *TypedSelect[T]) Wfts(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Wfts(column, value, opts...)
+	return q
+}
+
+// Cs adds a contains filter, like SelectRequestBuilder.Cs.
+func (q *TypedSelect[T]) Cs(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Cs(column, value, opts...)
+	return q
+}
+
+// Cd adds a contained by filter, like SelectRequestBuilder.Cd.
+func (q *TypedSelect[T]) Cd(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Cd(column, value, opts...)
+	return q
+}
+
+// Ov adds an overlap filter, like SelectRequestBuilder.Ov.
+func (q *TypedSelect[T]) Ov(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Ov(column, value, opts...)
+	return q
+}
+
+// Sl adds a strictly left of range filter, like SelectRequestBuilder.Sl.
+func (q *TypedSelect[T]) Sl(column string, from, to any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Sl(column, from, to, opts...)
+	return q
+}
+
+// Sr adds a strictly right of range filter, like SelectRequestBuilder.Sr.
+func (q *TypedSelect[T]) Sr(column string, from, to any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Sr(column, from, to, opts...)
+	return q
+}
+
+// Nxl adds a does not extend to the left of range filter, like SelectRequestBuilder.Nxl.
+func (q *TypedSelect[T]) Nxl(column string, from, to any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Nxl(column, from, to, opts...)
+	return q
+}
+
+// Nxr adds a does not extend to the right of range filter, like SelectRequestBuilder.Nxr.
+func (q *TypedSelect[T]) Nxr(column string, from, to any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Nxr(column, from, to, opts...)
+	return q
+}
+
+// Ad adds an adjacent range filter, like SelectRequestBuilder.Ad.
+func (q *TypedSelect[T]) Ad(column string, value any, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Ad(column, value, opts...)
+	return q
+}
+
+// Order adds an ordering column, like SelectRequestBuilder.Order.
+func (q *TypedSelect[T]) Order(column string, order Order, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Order(column, order, opts...)
+	return q
+}
+
+// Range sets the range of rows to be returned, like SelectRequestBuilder.Range.
+func (q *TypedSelect[T]) Range(from, to int, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Range(from, to, opts...)
+	return q
+}
+
+// Limit limits the number of rows, like SelectRequestBuilder.Limit.
+func (q *TypedSelect[T]) Limit(count int, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Limit(count, opts...)
+	return q
+}
+
+// Offset skips the number of rows, like SelectRequestBuilder.Offset.
+func (q *TypedSelect[T]) Offset(number int, opts ...QueryOption) *TypedSelect[T] {
+	q.SelectRequestBuilder.Offset(number, opts...)
+	return q
+}
+
+// Not negates the next filter condition, like FilterRequestBuilder.Not.
+func (q *TypedFilter[T]) Not() *TypedFilter[T] {
+	q.FilterRequestBuilder.Not()
+	return q
+}
+
+// Count requests the number of matched rows, read with ExecuteWithCount, like FilterRequestBuilder.Count.
+func (q *TypedFilter[T]) Count(count Count) *TypedFilter[T] {
+	q.FilterRequestBuilder.Count(count)
+	return q
+}
+
+// Filter adds a raw filter condition, like FilterRequestBuilder.Filter.
+func (q *TypedFilter[T]) Filter(column, operator, criteria string, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Filter(column, operator, criteria, opts...)
+	return q
+}
+
+// Or adds a group of conditions where any condition must match, like FilterRequestBuilder.Or.
+func (q *TypedFilter[T]) Or(group *FilterGroup, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Or(group, opts...)
+	return q
+}
+
+// And adds a group of conditions where every condition must match, like FilterRequestBuilder.And.
+func (q *TypedFilter[T]) And(group *FilterGroup, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.And(group, opts...)
+	return q
+}
+
+// Eq adds an equality filter, like FilterRequestBuilder.Eq.
+func (q *TypedFilter[T]) Eq(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Eq(column, value, opts...)
+	return q
+}
+
+// Neq adds a not equal filter, like FilterRequestBuilder.Neq.
+func (q *TypedFilter[T]) Neq(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Neq(column, value, opts...)
+	return q
+}
+
+// Gt adds a greater than filter, like FilterRequestBuilder.Gt.
+func (q *TypedFilter[T]) Gt(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Gt(column, value, opts...)
+	return q
+}
+
+// Gte adds a greater than or equal filter, like FilterRequestBuilder.Gte.
+func (q *TypedFilter[T]) Gte(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Gte(column, value, opts...)
+	return q
+}
+
+// Lt adds a less than filter, like FilterRequestBuilder.Lt.
+func (q *TypedFilter[T]) Lt(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Lt(column, value, opts...)
+	return q
+}
+
+// Lte adds a less than or equal filter, like FilterRequestBuilder.Lte.
+func (q *TypedFilter[T]) Lte(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Lte(column, value, opts...)
+	return q
+}
+
+// Like adds a case sensitive pattern filter, like FilterRequestBuilder.Like.
+func (q *TypedFilter[T]) Like(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Like(column, value, opts...)
+	return q
+}
+
+// Ilike adds a case insensitive pattern filter, like FilterRequestBuilder.Ilike.
+func (q *TypedFilter[T]) Ilike(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Ilike(column, value, opts...)
+	return q
+}
+
+// Is adds an is filter, e.g. for null, like FilterRequestBuilder.Is.
+func (q *TypedFilter[T]) Is(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Is(column, value, opts...)
+	return q
+}
+
+// In adds an in list filter, like FilterRequestBuilder.In.
+func (q *TypedFilter[T]) In(column string, values any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.In(column, values, opts...)
+	return q
+}
+
+// Fts adds a full text search filter, like FilterRequestBuilder.Fts.
+func (q *TypedFilter[T]) Fts(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Fts(column, value, opts...)
+	return q
+}
+
+// Plfts adds a plain full text search filter, like FilterRequestBuilder.Plfts.
+func (q *TypedFilter[T]) Plfts(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Plfts(column, value, opts...)
+	return q
+}
+
+// Wfts adds a web search full text search filter, like FilterRequestBuilder.Wfts.
+func (q *TypedFilter[T]) Wfts(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Wfts(column, value, opts...)
+	return q
+}
+
+// Cs adds a contains filter, like FilterRequestBuilder.Cs.
+func (q *TypedFilter[T]) Cs(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Cs(column, value, opts...)
+	return q
+}
+
+// Cd adds a contained by filter, like FilterRequestBuilder.Cd.
+func (q *TypedFilter[T]) Cd(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Cd(column, value, opts...)
+	return q
+}
+
+// Ov adds an overlap filter, like FilterRequestBuilder.Ov.
+func (q *TypedFilter[T]) Ov(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Ov(column, value, opts...)
+	return q
+}
+
+// Sl adds a strictly left of range filter, like FilterRequestBuilder.Sl.
+func (q *TypedFilter[T]) Sl(column string, from, to any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Sl(column, from, to, opts...)
+	return q
+}
+
+// Sr adds a strictly right of range filter, like FilterRequestBuilder.Sr.
+func (q *TypedFilter[T]) Sr(column string, from, to any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Sr(column, from, to, opts...)
+	return q
+}
+
+// Nxl adds a does not extend to the left of range filter, like FilterRequestBuilder.Nxl.
+func (q *TypedFilter[T]) Nxl(column string, from, to any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Nxl(column, from, to, opts...)
+	return q
+}
+
+// Nxr adds a does not extend to the right of range filter, like FilterRequestBuilder.Nxr.
+func (q *TypedFilter[T]) Nxr(column string, from, to any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Nxr(column, from, to, opts...)
+	return q
+}
+
+// Ad adds an adjacent range filter, like FilterRequestBuilder.Ad.
+func (q *TypedFilter[T]) Ad(column string, value any, opts ...QueryOption) *TypedFilter[T] {
+	q.FilterRequestBuilder.Ad(column, value, opts...)
+	return q
+}
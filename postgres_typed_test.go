@@ -0,0 +1,161 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+type testTodo struct {
+	ID   int64  `json:"id,omitempty"`
+	Task string `json:"task"`
+	Done bool   `json:"done"`
+}
+
+func (testTodo) TableName() string { return "todos" }
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   string
+}
+
+// newTestPostgres serves each request with the next response and records the requests.
+func newTestPostgres(t *testing.T, responses ...string) (*PostgresClient, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	client := newTestPostgresHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.Query(),
+			header: r.Header,
+			body:   string(body),
+		})
+		if len(requests) > len(responses) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(responses[len(requests)-1]))
+	})
+	return client, &requests
+}
+
+// newTestPostgresHandler returns a client of a PostgREST served by handler.
+func newTestPostgresHandler(t *testing.T, handler http.HandlerFunc) *PostgresClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client := NewPostgres("test")
+	base, err := url.Parse(srv.URL + restAPIPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.baseURL = *base
+	return client
+}
+
+func TestTypedTableReadme(t *testing.T) {
+	db, requests := newTestPostgres(t,
+		`{"id":1,"task":"write docs","done":false}`,
+		`[{"id":1,"task":"write docs"}]`,
+		`{"id":1,"task":"write docs","done":true}`,
+	)
+	ctx := context.Background()
+	todos, err := Table[testTodo](db, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	todo, err := todos.Insert(ctx, testTodo{Task: "write docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo.ID != 1 {
+		t.Fatalf("inserted todo = %+v", todo)
+	}
+
+	open, err := todos.Select("id", "task").Eq("done", false).Order("id", OrderAsc).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].Task != "write docs" {
+		t.Fatalf("open todos = %+v", open)
+	}
+
+	done, err := todos.Update(map[string]any{"done": true}).Eq("id", todo.ID).One(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done.Done {
+		t.Fatalf("updated todo = %+v", done)
+	}
+
+	reqs := *requests
+	if len(reqs) != 3 {
+		t.Fatalf("got %d requests, want 3", len(reqs))
+	}
+	if got := reqs[0]; got.method != http.MethodPost || got.path != "/rest/v1/todos" || got.body != `{"task":"write docs","done":false}` {
+		t.Errorf("insert request = %+v", got)
+	}
+	if got := reqs[1].query; got.Get("select") != "id,task" || got.Get("done") != "eq.false" || got.Get("order") != "id.asc" {
+		t.Errorf("select query = %v", got)
+	}
+	update := reqs[2]
+	if update.method != http.MethodPatch || update.query.Get("id") != "eq.1" || len(update.query) != 1 {
+		t.Errorf("update request = %+v", update)
+	}
+	var values map[string]any
+	if err = json.Unmarshal([]byte(update.body), &values); err != nil || values["done"] != true {
+		t.Errorf("update body = %s", update.body)
+	}
+	if got := update.header.Get("Accept"); got != "application/vnd.pgrst.object+json" {
+		t.Errorf("update accept = %s", got)
+	}
+}
+
+type testNamedTodo struct{}
+
+func (*testNamedTodo) TableName() string { return "named_todos" }
+
+func TestTypedTableName(t *testing.T) {
+	db, _ := newTestPostgres(t)
+	tests := []struct {
+		name  string
+		table func() (string, error)
+		want  string
+		err   error
+	}{
+		{name: "inferred", table: tableName[testTodo](db, ""), want: "todos"},
+		{name: "inferred from pointer", table: tableName[testNamedTodo](db, ""), want: "named_todos"},
+		{name: "explicit", table: tableName[testTodo](db, "tasks"), want: "tasks"},
+		{name: "missing", table: tableName[map[string]interface{}](db, ""), err: ErrTableName},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.table()
+			if !errors.Is(err, tc.err) || got != tc.want {
+				t.Errorf("Table() = %q, %v, want %q, %v", got, err, tc.want, tc.err)
+			}
+		})
+	}
+}
+
+func tableName[T any](db PostgresAPI, table string) func() (string, error) {
+	return func() (string, error) {
+		typed, err := Table[T](db, table)
+		if err != nil {
+			return "", err
+		}
+		return typed.table, nil
+	}
+}